@@ -0,0 +1,571 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/noborus/ov/oviewer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// projectConfigName is the name of the per-directory config file.
+const projectConfigName = ".ov.yaml"
+
+// includeKey is the key of the files included by the config file.
+const includeKey = "include"
+
+// projectIgnoreKeys are the keys that are ignored in the per-directory config files,
+// because they run the commands.
+var projectIgnoreKeys = []string{"preprocessor", "clipboardcopycommand", "clipboardpastecommand"}
+
+var (
+	// configFiles is the config files read in the order of merging.
+	configFiles []string
+	// configSources is the file that set each key of the config.
+	configSources = make(map[string]string)
+	// flagKeys is the key of the config bound to each flag.
+	flagKeys = make(map[string]string)
+)
+
+var (
+	// ErrInvalidConfig indicates that problems were found in the config file.
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrIncludeLoop indicates that the config file includes itself.
+	ErrIncludeLoop = errors.New("include loop")
+)
+
+// configCmd represents the config command.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "check or dump the config",
+	Long: `check or dump the config.
+"ov config" displays the file named config if it exists.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Keep "ov config" working for the file named config.
+		if fileExists(cmd.Use) {
+			return rootCmd.RunE(rootCmd, append([]string{cmd.Use}, args...))
+		}
+		return cmd.Help()
+	},
+}
+
+// configCheckCmd represents the config check command.
+var configCheckCmd = &cobra.Command{
+	Use:          "check",
+	Short:        "check the config file",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return ConfigCheck(cmd.OutOrStdout(), configFiles, config)
+	},
+}
+
+// configDumpCmd represents the config dump command.
+var configDumpCmd = &cobra.Command{
+	Use:          "dump",
+	Short:        "print the effective config as YAML",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return ConfigDump(cmd.OutOrStdout(), config, configProvenance())
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configDumpCmd)
+	rootCmd.AddCommand(configCmd)
+	// The root command with the subcommand rejects the file arguments unless they are allowed.
+	rootCmd.Args = cobra.ArbitraryArgs
+}
+
+// bindFlag binds the flag to the key of the config.
+func bindFlag(key string, name string) {
+	flagKeys[strings.ToLower(key)] = name
+	_ = viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(name))
+}
+
+// readLayeredConfig merges the config files into viper in the following order.
+// The later file overrides the earlier one.
+//
+//  1. the files included by the user config file
+//  2. the user config file
+//  3. the per-directory config files (.ov.yaml) from the outermost directory to the current directory,
+//     each after the files it includes
+func readLayeredConfig(userFile string) error {
+	l := &configLayer{
+		sources: make(map[string]string),
+		reading: make(map[string]bool),
+	}
+	merged := make(map[string]any)
+	if userFile != "" {
+		if err := l.read(merged, userFile, false); err != nil {
+			return err
+		}
+	}
+	for _, fileName := range projectConfigFiles() {
+		if err := l.read(merged, fileName, true); err != nil {
+			return err
+		}
+	}
+	configFiles = l.files
+	configSources = l.sources
+	if len(l.files) == 0 {
+		return nil
+	}
+	return viper.MergeConfigMap(merged)
+}
+
+// projectConfigFiles returns the per-directory config files
+// from the outermost directory to the current directory.
+// The home directory and above are not searched,
+// because ~/.ov.yaml is the legacy user config file.
+func projectConfigFiles() []string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	var files []string
+	for {
+		if dir == home {
+			break
+		}
+		fileName := filepath.Join(dir, projectConfigName)
+		if fileExists(fileName) {
+			files = append([]string{fileName}, files...)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return files
+}
+
+// configLayer reads the config files and records the file that set each key.
+type configLayer struct {
+	// files is the files read in the order of merging.
+	files []string
+	// sources is the file that set each key (e.g. "general.tabwidth").
+	sources map[string]string
+	// reading is the files being read, to detect include loops.
+	reading map[string]bool
+}
+
+// read merges the files included by the file and then the file into dst.
+func (l *configLayer) read(dst map[string]any, fileName string, project bool) error {
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return err
+	}
+	if l.reading[abs] {
+		return fmt.Errorf("%w: %s", ErrIncludeLoop, fileName)
+	}
+	l.reading[abs] = true
+	defer delete(l.reading, abs)
+
+	buf, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(buf, &m); err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+	m = lowerKeys(m)
+
+	includes, err := includeFiles(filepath.Dir(fileName), m[includeKey])
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+	delete(m, includeKey)
+	for _, include := range includes {
+		if err := l.read(dst, include, project); err != nil {
+			return err
+		}
+	}
+	if project {
+		for _, key := range projectIgnoreKeys {
+			delete(m, key)
+		}
+	}
+	l.files = append(l.files, fileName)
+	mergeConfig(dst, m, "", fileName, l.sources)
+	return nil
+}
+
+// includeFiles returns the files of the include directive.
+// The value is a file name or a list of file names, relative to the directory of the including file.
+// "~/" is the home directory, and the glob pattern is expanded in sorted order.
+func includeFiles(dir string, value any) ([]string, error) {
+	var names []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = []string{v}
+	case []any:
+		for _, n := range v {
+			names = append(names, fmt.Sprint(n))
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, includeKey, v)
+	}
+
+	var files []string
+	for _, name := range names {
+		if rest, ok := strings.CutPrefix(name, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			name = filepath.Join(home, rest)
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		if !strings.ContainsAny(name, "*?[") {
+			files = append(files, name)
+			continue
+		}
+		matches, err := filepath.Glob(name)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// lowerKeys returns the map with the lowercase keys, as viper does.
+func lowerKeys(m map[string]any) map[string]any {
+	lower := make(map[string]any, len(m))
+	for k, v := range m {
+		if vm, ok := v.(map[string]any); ok {
+			v = lowerKeys(vm)
+		}
+		lower[strings.ToLower(k)] = v
+	}
+	return lower
+}
+
+// mergeConfig deep-merges src into dst and records the source of the keys.
+// The maps are merged key by key, and the other values (including the lists) are replaced.
+func mergeConfig(dst map[string]any, src map[string]any, prefix string, source string, sources map[string]string) {
+	for k, v := range src {
+		key := prefix + k
+		if vm, ok := v.(map[string]any); ok {
+			dm, ok := dst[k].(map[string]any)
+			if !ok {
+				dm = make(map[string]any)
+				dst[k] = dm
+			}
+			mergeConfig(dm, vm, key+".", source, sources)
+			continue
+		}
+		dst[k] = v
+		for s := range sources {
+			if strings.HasPrefix(s, key+".") {
+				delete(sources, s)
+			}
+		}
+		sources[key] = source
+	}
+}
+
+// configProvenance returns the source of each key of the config.
+// The options override the environment variables, which override the config files.
+func configProvenance() map[string]string {
+	sources := make(map[string]string, len(configSources))
+	for k, v := range configSources {
+		sources[k] = v
+	}
+	for _, key := range viper.AllKeys() {
+		env := "OV_" + strings.ToUpper(key)
+		if _, ok := os.LookupEnv(env); ok {
+			sources[key] = "env " + env
+		}
+	}
+	for key, name := range flagKeys {
+		if f := rootCmd.PersistentFlags().Lookup(name); f != nil && f.Changed {
+			sources[key] = "flag --" + name
+		}
+	}
+	return sources
+}
+
+// configProblem is a problem at the position of the config file.
+type configProblem struct {
+	file   string
+	line   int
+	column int
+	msg    string
+}
+
+// ConfigCheck reports the problems of the config files with the position.
+// The unknown keys are found in each file,
+// and the other problems in the merged config are reported at the file that set the key.
+func ConfigCheck(w io.Writer, files []string, cfg oviewer.Config) error {
+	configType := reflect.TypeOf(oviewer.Config{})
+	docs := make([]*yaml.Node, len(files))
+	var problems []configProblem
+	for i, fileName := range files {
+		buf, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		var root yaml.Node
+		if err := yaml.Unmarshal(buf, &root); err != nil {
+			return fmt.Errorf("%s: %w", fileName, err)
+		}
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			docs[i] = root.Content[0]
+		}
+		for _, p := range unknownKeys(docs[i], configType, nil) {
+			p.file = fileName
+			problems = append(problems, p)
+		}
+	}
+
+	for _, e := range oviewer.CheckConfig(cfg) {
+		p := configProblem{file: "(default)", msg: e.Error()}
+		if len(files) > 0 {
+			p.file = files[len(files)-1]
+		}
+		// The last file that has the key.
+		for i := len(docs) - 1; i >= 0; i-- {
+			if n, ok := findNode(docs[i], configType, e.Path); ok {
+				p.file, p.line, p.column = files[i], n.Line, n.Column
+				break
+			}
+		}
+		problems = append(problems, p)
+	}
+	order := make(map[string]int, len(files))
+	for i, fileName := range files {
+		order[fileName] = i
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].file != problems[j].file {
+			return order[problems[i].file] < order[problems[j].file]
+		}
+		if problems[i].line != problems[j].line {
+			return problems[i].line < problems[j].line
+		}
+		return problems[i].column < problems[j].column
+	})
+
+	for _, p := range problems {
+		if p.line > 0 {
+			fmt.Fprintf(w, "%s:%d:%d: %s\n", p.file, p.line, p.column, p.msg)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", p.file, p.msg)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problem(s)", ErrInvalidConfig, len(problems))
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(w, "(default): ok")
+	}
+	for _, fileName := range files {
+		fmt.Fprintf(w, "%s: ok\n", fileName)
+	}
+	return nil
+}
+
+// unknownKeys returns the keys of the node that are not in the type.
+func unknownKeys(node *yaml.Node, t reflect.Type, path []string) []configProblem {
+	if node == nil {
+		return nil
+	}
+	var problems []configProblem
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if len(path) == 0 && strings.EqualFold(key.Value, includeKey) {
+				continue
+			}
+			keyPath := append(path[:len(path):len(path)], key.Value)
+			field, ok := configField(t, key.Value)
+			if !ok {
+				problems = append(problems, configProblem{
+					line:   key.Line,
+					column: key.Column,
+					msg:    "unknown key: " + strings.Join(keyPath, "."),
+				})
+				continue
+			}
+			problems = append(problems, unknownKeys(value, field.Type, keyPath)...)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := append(path[:len(path):len(path)], node.Content[i].Value)
+			problems = append(problems, unknownKeys(node.Content[i+1], t.Elem(), keyPath)...)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+		for i, n := range node.Content {
+			problems = append(problems, unknownKeys(n, t.Elem(), append(path[:len(path):len(path)], strconv.Itoa(i)))...)
+		}
+	}
+	return problems
+}
+
+// findNode returns the node of the key of the path.
+// It returns false if the path is not in the node.
+func findNode(node *yaml.Node, t reflect.Type, path []string) (*yaml.Node, bool) {
+	if node == nil || len(path) == 0 {
+		return node, node != nil
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return nil, false
+		}
+		elem := t
+		if t.Kind() == reflect.Map {
+			elem = t.Elem()
+		} else {
+			field, ok := configField(t, path[0])
+			if !ok {
+				return nil, false
+			}
+			elem = field.Type
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if strings.EqualFold(node.Content[i].Value, path[0]) {
+				if len(path) == 1 {
+					return node.Content[i], true
+				}
+				return findNode(node.Content[i+1], elem, path[1:])
+			}
+		}
+	case reflect.Slice:
+		n, err := strconv.Atoi(path[0])
+		if err != nil || node.Kind != yaml.SequenceNode || n >= len(node.Content) {
+			return nil, false
+		}
+		return findNode(node.Content[n], t.Elem(), path[1:])
+	}
+	return nil, false
+}
+
+// configField returns the field of the struct that matches the key case-insensitively.
+func configField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Type.Kind() == reflect.Pointer {
+			continue
+		}
+		if strings.EqualFold(field.Name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// ConfigDump prints the effective config as YAML.
+// The source of each value (default, file, env or flag) is added as a comment.
+// If sources is nil, no comment is added.
+func ConfigDump(w io.Writer, cfg oviewer.Config, sources map[string]string) error {
+	node := configNode(reflect.ValueOf(cfg), "", sources)
+	if len(configFiles) > 0 {
+		node.HeadComment = "config files:\n" + strings.Join(configFiles, "\n")
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// configNode returns the YAML node of the value.
+// The fields of the struct keep the order of the definition,
+// and the keys of the map are sorted.
+func configNode(v reflect.Value, prefix string, sources map[string]string) *yaml.Node {
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Type.Kind() == reflect.Pointer {
+				continue
+			}
+			node.Content = append(node.Content, configPair(field.Name, v.Field(i), prefix, sources)...)
+		}
+		return node
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, k := range keys {
+			node.Content = append(node.Content, configPair(k.String(), v.MapIndex(k), prefix, sources)...)
+		}
+		return node
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if v.Type().Elem().Kind() == reflect.String || v.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for i := 0; i < v.Len(); i++ {
+			// The elements of the list have the source of the list.
+			node.Content = append(node.Content, configNode(v.Index(i), "", nil))
+		}
+		return node
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(v.Interface()); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v.Interface())}
+	}
+	return node
+}
+
+// configPair returns the key and value nodes with the source of the value as a comment.
+// The value that is not set by a file, env or flag is "default".
+// The mapping has no comment, because its keys have the sources.
+func configPair(name string, v reflect.Value, prefix string, sources map[string]string) []*yaml.Node {
+	key := prefix + strings.ToLower(name)
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+	valueNode := configNode(v, key+".", sources)
+	if sources == nil {
+		return []*yaml.Node{keyNode, valueNode}
+	}
+	source, ok := sources[key]
+	if !ok {
+		if valueNode.Kind == yaml.MappingNode {
+			return []*yaml.Node{keyNode, valueNode}
+		}
+		source = "default"
+	}
+	if valueNode.Kind == yaml.ScalarNode || valueNode.Style == yaml.FlowStyle {
+		valueNode.LineComment = source
+	} else {
+		keyNode.LineComment = source
+	}
+	return []*yaml.Node{keyNode, valueNode}
+}
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/noborus/ov/oviewer"
@@ -59,3 +64,182 @@ func Test_Config(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			yaml: "General:\n  TabWidth: 4\n",
+			want: []string{"ok"},
+		},
+		{
+			name:    "unknown key",
+			yaml:    "StyleHeader:\n  Bolt: true\n",
+			want:    []string{":2:3: unknown key: StyleHeader.Bolt"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid color",
+			yaml:    "StyleHeader:\n  Foreground: notacolor\n",
+			want:    []string{":2:3: StyleHeader.Foreground: invalid color: notacolor"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid regexp",
+			yaml:    "General:\n  MultiColorWords:\n    - ok\n    - (bad\n",
+			want:    []string{":4:7: General.MultiColorWords.1: invalid regular expression"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			yaml:    "Keybind:\n  exitx:\n    - q\n",
+			want:    []string{":2:3: Keybind.exitx: unknown action"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(fileName, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			v := viper.New()
+			v.SetConfigFile(fileName)
+			if err := v.ReadInConfig(); err != nil {
+				t.Fatal(err)
+			}
+			cfg := oviewer.NewConfig()
+			if err := v.Unmarshal(&cfg); err != nil {
+				t.Fatal(err)
+			}
+			w := &bytes.Buffer{}
+			if err := ConfigCheck(w, []string{fileName}, cfg); (err != nil) != tt.wantErr {
+				t.Errorf("ConfigCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.String(), want) {
+					t.Errorf("ConfigCheck() = %v, want %v", w.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigDump(t *testing.T) {
+	cfg := oviewer.NewConfig()
+	cfg.Keybind = map[string][]string{"exit": {"q"}}
+	cfg.General.Header = 2
+	sources := map[string]string{
+		"keybind.exit":   "config.yaml",
+		"general.header": "flag --header",
+	}
+	w := &bytes.Buffer{}
+	if err := ConfigDump(w, cfg, sources); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Keybind:\n  exit: [q] # config.yaml\n",
+		"  Header: 2 # flag --header\n",
+		"  TabWidth: 8 # default\n",
+		"MemoryLimitFile: 100 # default\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("ConfigDump() = %v, want %v", w.String(), want)
+		}
+	}
+	if strings.Contains(w.String(), "ColumnDelimiterReg") {
+		t.Errorf("ConfigDump() = %v, contains ColumnDelimiterReg", w.String())
+	}
+	// The dump can be read as the config.
+	var got oviewer.Config
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.General.Header != 2 || got.MemoryLimitFile != 100 || len(got.Keybind["exit"]) != 1 {
+		t.Errorf("ConfigDump() read = %v", got.General)
+	}
+}
+
+func TestConfigLayer(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name string, s string) string {
+		t.Helper()
+		fileName := filepath.Join(dir, name)
+		if err := os.WriteFile(fileName, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return fileName
+	}
+	writeFile("base.yaml", "General:\n  TabWidth: 4\n  Header: 1\nKeybind:\n  exit: [Q]\nMode:\n  csv:\n    ColumnMode: true\n")
+	writeFile("loop.yaml", "include: loop.yaml\n")
+	user := writeFile("user.yaml", "include: base.yaml\nGeneral:\n  Header: 2\nMode:\n  csv:\n    ColumnRainbow: true\n")
+	project := writeFile("project.yaml", "Keybind:\n  top: [t]\nPreprocessor:\n  - Command: [cat]\n")
+
+	l := &configLayer{sources: make(map[string]string), reading: make(map[string]bool)}
+	merged := make(map[string]any)
+	if err := l.read(merged, user, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.read(merged, project, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(l.files) != 3 || l.files[0] != filepath.Join(dir, "base.yaml") {
+		t.Errorf("configLayer.files = %v", l.files)
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(merged); err != nil {
+		t.Fatal(err)
+	}
+	var got oviewer.Config
+	if err := v.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.General.TabWidth != 4 || got.General.Header != 2 {
+		t.Errorf("General = %v", got.General)
+	}
+	if csv := got.Mode["csv"]; !csv.ColumnMode || !csv.ColumnRainbow {
+		t.Errorf("Mode[csv] = %v", csv)
+	}
+	if len(got.Keybind["exit"]) != 1 || len(got.Keybind["top"]) != 1 {
+		t.Errorf("Keybind = %v", got.Keybind)
+	}
+	if len(got.Preprocessor) != 0 {
+		t.Errorf("Preprocessor = %v, want ignored in the project file", got.Preprocessor)
+	}
+
+	wantSources := map[string]string{
+		"general.tabwidth":       filepath.Join(dir, "base.yaml"),
+		"general.header":         user,
+		"mode.csv.columnrainbow": user,
+		"keybind.top":            project,
+	}
+	for key, want := range wantSources {
+		if l.sources[key] != want {
+			t.Errorf("sources[%s] = %v, want %v", key, l.sources[key], want)
+		}
+	}
+
+	w := &bytes.Buffer{}
+	if err := ConfigDump(w, got, l.sources); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Header: 2 # " + user; !strings.Contains(w.String(), want) {
+		t.Errorf("ConfigDump() = %v, want %v", w.String(), want)
+	}
+
+	l = &configLayer{sources: make(map[string]string), reading: make(map[string]bool)}
+	if err := l.read(make(map[string]any), filepath.Join(dir, "loop.yaml"), false); !errors.Is(err, ErrIncludeLoop) {
+		t.Errorf("configLayer.read() error = %v, want %v", err, ErrIncludeLoop)
+	}
+}
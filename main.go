@@ -37,6 +37,12 @@ var (
 	// non match filter pattern.
 	nonMatchFilter string
 
+	// runMacro is the name of the macro to run at startup.
+	runMacro string
+
+	// listen is the path of the remote control socket.
+	listen string
+
 	// ver is version information.
 	ver bool
 	// helpKey is key bind information.
@@ -64,10 +70,10 @@ It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if config.Debug {
-			cfg := viper.ConfigFileUsed()
-			if cfg != "" {
+			for _, cfg := range configFiles {
 				fmt.Fprintln(os.Stderr, "Using config file:", cfg)
-			} else {
+			}
+			if len(configFiles) == 0 {
 				fmt.Fprintln(os.Stderr, "config file not found")
 			}
 		}
@@ -84,26 +90,14 @@ It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
 		if completion != "" {
 			return Completion(cmd, completion)
 		}
-		// Set the caption from the environment variable.
-		if config.General.Caption == "" {
-			config.General.Caption = viper.GetString("CAPTION")
-		}
-
-		// Actually tabs when "\t" is specified as an option.
-		if config.General.ColumnDelimiter == "\\t" {
-			config.General.ColumnDelimiter = "\t"
-		}
-
-		// SectionHeader is enabled if SectionHeaderNum is greater than 0.
-		if config.General.SectionHeaderNum > 0 {
-			config.General.SectionHeader = true
-		}
+		adjustConfig(&config)
 
 		// Set a global variable to convert to a style before opening the file.
 		oviewer.OverStrikeStyle = oviewer.ToTcellStyle(config.StyleOverStrike)
 		oviewer.OverLineStyle = oviewer.ToTcellStyle(config.StyleOverLine)
 		oviewer.MemoryLimit = config.MemoryLimit
 		oviewer.MemoryLimitFile = config.MemoryLimitFile
+		oviewer.Preprocessors = config.Preprocessor
 		SetRedirect()
 
 		if execCommand {
@@ -113,6 +107,47 @@ It supports various compressed files(gzip, bzip2, zstd, lz4, and xz).
 	},
 }
 
+// adjustConfig adjusts the config read from the config file, the environment variables and the options.
+func adjustConfig(c *oviewer.Config) {
+	// Set the caption from the environment variable.
+	if c.General.Caption == "" {
+		c.General.Caption = viper.GetString("CAPTION")
+	}
+
+	// Actually tabs when "\t" is specified as an option.
+	if c.General.ColumnDelimiter == "\\t" {
+		c.General.ColumnDelimiter = "\t"
+	}
+
+	// SectionHeader is enabled if SectionHeaderNum is greater than 0.
+	if c.General.SectionHeaderNum > 0 {
+		c.General.SectionHeader = true
+	}
+}
+
+// reloadConfig reads the config files again for the live reload.
+// It returns the config files read, including the included files and the per-directory config files.
+func reloadConfig() (oviewer.Config, []string, error) {
+	userFile := ""
+	if err := viper.ReadInConfig(); err != nil {
+		var configNotFoundError viper.ConfigFileNotFoundError
+		if !errors.As(err, &configNotFoundError) {
+			return oviewer.Config{}, nil, err
+		}
+	} else {
+		userFile = viper.ConfigFileUsed()
+	}
+	if err := readLayeredConfig(userFile); err != nil {
+		return oviewer.Config{}, nil, err
+	}
+	c := oviewer.NewConfig()
+	if err := viper.Unmarshal(&c); err != nil {
+		return oviewer.Config{}, nil, err
+	}
+	adjustConfig(&c)
+	return c, configFiles, nil
+}
+
 // HelpKey displays key bindings and exits.
 func HelpKey(cmd *cobra.Command, _ []string) {
 	fmt.Println(cmd.Short)
@@ -163,6 +198,7 @@ func RunOviewer(args []string) error {
 	}
 
 	ov.SetConfig(config)
+	ov.WatchConfig(configFiles, reloadConfig)
 
 	if pattern != "" {
 		ov.Search(pattern)
@@ -173,6 +209,16 @@ func RunOviewer(args []string) error {
 	if nonMatchFilter != "" {
 		ov.Filter(nonMatchFilter, true)
 	}
+	if runMacro != "" {
+		if err := ov.RunMacro(runMacro); err != nil {
+			return err
+		}
+	}
+	if listen != "" {
+		if err := ov.Listen(listen); err != nil {
+			return err
+		}
+	}
 
 	if err := ov.Run(); err != nil {
 		return err
@@ -203,6 +249,7 @@ func ExecCommand(args []string) error {
 	}()
 
 	ov.SetConfig(config)
+	ov.WatchConfig(configFiles, reloadConfig)
 
 	if err := ov.Run(); err != nil {
 		return err
@@ -352,138 +399,179 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&pattern, "pattern", "", "", "search pattern")
 	rootCmd.PersistentFlags().StringVarP(&filter, "filter", "", "", "filter search pattern")
 	rootCmd.PersistentFlags().StringVarP(&nonMatchFilter, "non-match-filter", "", "", "filter non match search pattern")
+	rootCmd.PersistentFlags().StringVarP(&listen, "listen", "", "", "listen on the unix socket for remote control")
+	rootCmd.PersistentFlags().StringVarP(&runMacro, "run-macro", "", "", "run the macro at startup")
 	rootCmd.PersistentFlags().BoolVarP(&oviewer.SkipExtract, "skip-extract", "", false, "skip extracting compressed files")
 
 	// Config.General
 	rootCmd.PersistentFlags().IntP("tab-width", "x", 8, "tab stop width")
-	_ = viper.BindPFlag("general.TabWidth", rootCmd.PersistentFlags().Lookup("tab-width"))
+	bindFlag("general.TabWidth", "tab-width")
 	_ = rootCmd.RegisterFlagCompletionFunc("tab-width", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"3\ttab width", "2\ttab width", "4\ttab width", "8\ttab width"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	rootCmd.PersistentFlags().IntP("header", "H", 0, "number of header lines to be displayed constantly")
-	_ = viper.BindPFlag("general.Header", rootCmd.PersistentFlags().Lookup("header"))
+	bindFlag("general.Header", "header")
 	_ = rootCmd.RegisterFlagCompletionFunc("header", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"1"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	rootCmd.PersistentFlags().IntP("skip-lines", "", 0, "skip the number of lines")
-	_ = viper.BindPFlag("general.SkipLines", rootCmd.PersistentFlags().Lookup("skip-lines"))
+	bindFlag("general.SkipLines", "skip-lines")
 	_ = rootCmd.RegisterFlagCompletionFunc("skip-lines", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"1"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	rootCmd.PersistentFlags().BoolP("alternate-rows", "C", false, "alternately change the line color")
-	_ = viper.BindPFlag("general.AlternateRows", rootCmd.PersistentFlags().Lookup("alternate-rows"))
+	bindFlag("general.AlternateRows", "alternate-rows")
 
 	rootCmd.PersistentFlags().BoolP("column-mode", "c", false, "column mode")
-	_ = viper.BindPFlag("general.ColumnMode", rootCmd.PersistentFlags().Lookup("column-mode"))
+	bindFlag("general.ColumnMode", "column-mode")
 
 	rootCmd.PersistentFlags().BoolP("column-width", "", false, "column mode for width")
-	_ = viper.BindPFlag("general.ColumnWidth", rootCmd.PersistentFlags().Lookup("column-width"))
+	bindFlag("general.ColumnWidth", "column-width")
 
 	rootCmd.PersistentFlags().BoolP("column-rainbow", "", false, "column mode to rainbow")
-	_ = viper.BindPFlag("general.ColumnRainbow", rootCmd.PersistentFlags().Lookup("column-rainbow"))
+	bindFlag("general.ColumnRainbow", "column-rainbow")
+
+	rootCmd.PersistentFlags().BoolP("column-csv", "", false, "parse quoted columns as CSV (always for , and tab)")
+	bindFlag("general.ColumnCSV", "column-csv")
+
+	rootCmd.PersistentFlags().BoolP("column-align", "", false, "align delimited columns as a table")
+	bindFlag("general.ColumnAlign", "column-align")
+
+	rootCmd.PersistentFlags().StringP("column-align-separator", "", "", "separator between aligned columns (default \"  \")")
+	bindFlag("general.ColumnAlignSeparator", "column-align-separator")
+
+	rootCmd.PersistentFlags().IntP("column-max-width", "", 0, "maximum width of aligned columns (0 is unlimited)")
+	bindFlag("general.ColumnMaxWidth", "column-max-width")
+
+	rootCmd.PersistentFlags().IntSliceP("column-hide", "", nil, "columns to hide (numbered from 1)")
+	bindFlag("general.ColumnHide", "column-hide")
+
+	rootCmd.PersistentFlags().IntSliceP("column-order", "", nil, "columns to display first, in this order (numbered from 1)")
+	bindFlag("general.ColumnOrder", "column-order")
+
+	rootCmd.PersistentFlags().IntP("column-freeze", "", 0, "number of columns on the left that are not scrolled")
+	bindFlag("general.ColumnFreeze", "column-freeze")
+
+	rootCmd.PersistentFlags().IntSliceP("column-positions", "", nil, "start positions of fixed-width columns (numbered from 1)")
+	bindFlag("general.ColumnPositions", "column-positions")
+
+	rootCmd.PersistentFlags().StringP("column", "", "", "column to select at startup (header name or number from 1)")
+	bindFlag("general.Column", "column")
 
 	rootCmd.PersistentFlags().BoolP("line-number", "n", false, "line number mode")
-	_ = viper.BindPFlag("general.LineNumMode", rootCmd.PersistentFlags().Lookup("line-number"))
+	bindFlag("general.LineNumMode", "line-number")
 
 	rootCmd.PersistentFlags().BoolP("wrap", "w", true, "wrap mode")
-	_ = viper.BindPFlag("general.WrapMode", rootCmd.PersistentFlags().Lookup("wrap"))
+	bindFlag("general.WrapMode", "wrap")
 
 	rootCmd.PersistentFlags().BoolP("plain", "p", false, "disable original decoration")
-	_ = viper.BindPFlag("general.PlainMode", rootCmd.PersistentFlags().Lookup("plain"))
+	bindFlag("general.PlainMode", "plain")
 
 	rootCmd.PersistentFlags().StringP("column-delimiter", "d", ",", "column delimiter `character`")
-	_ = viper.BindPFlag("general.ColumnDelimiter", rootCmd.PersistentFlags().Lookup("column-delimiter"))
+	bindFlag("general.ColumnDelimiter", "column-delimiter")
 	_ = rootCmd.RegisterFlagCompletionFunc("column-delimiter", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{",\tcomma", "|\tvertical line", "\\\\t\ttab", "│\tbox"}, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	rootCmd.PersistentFlags().StringP("section-delimiter", "", "", "`regexp` for section delimiter .e.g. \"^#\"")
-	_ = viper.BindPFlag("general.SectionDelimiter", rootCmd.PersistentFlags().Lookup("section-delimiter"))
+	bindFlag("general.SectionDelimiter", "section-delimiter")
 
 	rootCmd.PersistentFlags().IntP("section-start", "", 0, "section start position")
-	_ = viper.BindPFlag("general.SectionStartPosition", rootCmd.PersistentFlags().Lookup("section-start"))
+	bindFlag("general.SectionStartPosition", "section-start")
 
 	rootCmd.PersistentFlags().BoolP("section-header", "", false, "enable section-delimiter line as Header")
-	_ = viper.BindPFlag("general.SectionHeader", rootCmd.PersistentFlags().Lookup("section-header"))
+	bindFlag("general.SectionHeader", "section-header")
 
 	rootCmd.PersistentFlags().IntP("section-header-num", "", 1, "number of section header lines")
-	_ = viper.BindPFlag("general.SectionHeaderNum", rootCmd.PersistentFlags().Lookup("section-header-num"))
+	bindFlag("general.SectionHeaderNum", "section-header-num")
 
 	rootCmd.PersistentFlags().BoolP("follow-mode", "f", false, "monitor file and display new content as it is written")
-	_ = viper.BindPFlag("general.FollowMode", rootCmd.PersistentFlags().Lookup("follow-mode"))
+	bindFlag("general.FollowMode", "follow-mode")
 
 	rootCmd.PersistentFlags().BoolP("follow-all", "A", false, "follow multiple files and show the most recently updated one")
-	_ = viper.BindPFlag("general.FollowAll", rootCmd.PersistentFlags().Lookup("follow-all"))
+	bindFlag("general.FollowAll", "follow-all")
 
 	rootCmd.PersistentFlags().BoolP("follow-section", "", false, "section-by-section follow mode")
-	_ = viper.BindPFlag("general.FollowSection", rootCmd.PersistentFlags().Lookup("follow-section"))
+	bindFlag("general.FollowSection", "follow-section")
 
 	rootCmd.PersistentFlags().BoolP("follow-name", "", false, "follow mode to monitor by file name")
-	_ = viper.BindPFlag("general.FollowName", rootCmd.PersistentFlags().Lookup("follow-name"))
+	bindFlag("general.FollowName", "follow-name")
 
 	rootCmd.PersistentFlags().IntP("watch", "T", 0, "watch mode interval(`seconds`)")
-	_ = viper.BindPFlag("general.WatchInterval", rootCmd.PersistentFlags().Lookup("watch"))
+	bindFlag("general.WatchInterval", "watch")
 
 	rootCmd.PersistentFlags().StringSliceP("multi-color", "M", nil, "comma separated words(regexp) to color .e.g. \"ERROR,WARNING\"")
-	_ = viper.BindPFlag("general.MultiColorWords", rootCmd.PersistentFlags().Lookup("multi-color"))
+	bindFlag("general.MultiColorWords", "multi-color")
 
 	rootCmd.PersistentFlags().StringP("jump-target", "j", "", "jump target `[int|int%|.int|'section']`")
-	_ = viper.BindPFlag("general.JumpTarget", rootCmd.PersistentFlags().Lookup("jump-target"))
+	bindFlag("general.JumpTarget", "jump-target")
 
 	rootCmd.PersistentFlags().StringP("hscroll-width", "", "10%", "width to scroll horizontally `[int|int%|.int]`")
-	_ = viper.BindPFlag("general.HScrollWidth", rootCmd.PersistentFlags().Lookup("hscroll-width"))
+	bindFlag("general.HScrollWidth", "hscroll-width")
 
 	rootCmd.PersistentFlags().StringP("caption", "", "", "custom caption")
-	_ = viper.BindPFlag("general.Caption", rootCmd.PersistentFlags().Lookup("caption"))
+	bindFlag("general.Caption", "caption")
 
 	rootCmd.PersistentFlags().BoolP("hide-other-section", "", false, "hide other section")
-	_ = viper.BindPFlag("general.HideOtherSection", rootCmd.PersistentFlags().Lookup("hide-other-section"))
+	bindFlag("general.HideOtherSection", "hide-other-section")
 
 	// Config
 	rootCmd.PersistentFlags().BoolP("quit-if-one-screen", "F", false, "quit if the output fits on one screen")
-	_ = viper.BindPFlag("QuitSmall", rootCmd.PersistentFlags().Lookup("quit-if-one-screen"))
+	bindFlag("QuitSmall", "quit-if-one-screen")
 
 	rootCmd.PersistentFlags().BoolP("exit-write", "X", false, "output the current screen when exiting")
-	_ = viper.BindPFlag("IsWriteOriginal", rootCmd.PersistentFlags().Lookup("exit-write"))
+	bindFlag("IsWriteOriginal", "exit-write")
 
 	rootCmd.PersistentFlags().IntP("exit-write-before", "b", 0, "number before the current lines when exiting")
-	_ = viper.BindPFlag("BeforeWriteOriginal", rootCmd.PersistentFlags().Lookup("exit-write-before"))
+	bindFlag("BeforeWriteOriginal", "exit-write-before")
 
 	rootCmd.PersistentFlags().IntP("exit-write-after", "a", 0, "number after the current lines when exiting")
-	_ = viper.BindPFlag("AfterWriteOriginal", rootCmd.PersistentFlags().Lookup("exit-write-after"))
+	bindFlag("AfterWriteOriginal", "exit-write-after")
 
 	rootCmd.PersistentFlags().BoolP("case-sensitive", "i", false, "case-sensitive in search")
-	_ = viper.BindPFlag("CaseSensitive", rootCmd.PersistentFlags().Lookup("case-sensitive"))
+	bindFlag("CaseSensitive", "case-sensitive")
 
 	rootCmd.PersistentFlags().BoolP("smart-case-sensitive", "", false, "smart case-sensitive in search")
-	_ = viper.BindPFlag("SmartCaseSensitive", rootCmd.PersistentFlags().Lookup("smart-case-sensitive"))
+	bindFlag("SmartCaseSensitive", "smart-case-sensitive")
 
 	rootCmd.PersistentFlags().BoolP("regexp-search", "", false, "regular expression search")
-	_ = viper.BindPFlag("RegexpSearch", rootCmd.PersistentFlags().Lookup("regexp-search"))
+	bindFlag("RegexpSearch", "regexp-search")
 
 	rootCmd.PersistentFlags().BoolP("incsearch", "", true, "incremental search")
-	_ = viper.BindPFlag("Incsearch", rootCmd.PersistentFlags().Lookup("incsearch"))
+	bindFlag("Incsearch", "incsearch")
 
 	rootCmd.PersistentFlags().IntP("memory-limit", "", -1, "number of chunks to limit in memory")
-	_ = viper.BindPFlag("MemoryLimit", rootCmd.PersistentFlags().Lookup("memory-limit"))
+	bindFlag("MemoryLimit", "memory-limit")
 
 	rootCmd.PersistentFlags().IntP("memory-limit-file", "", 100, "number of chunks to limit in memory for the file")
-	_ = viper.BindPFlag("MemoryLimitFile", rootCmd.PersistentFlags().Lookup("memory-limit-file"))
+	bindFlag("MemoryLimitFile", "memory-limit-file")
 
 	rootCmd.PersistentFlags().BoolP("disable-mouse", "", false, "disable mouse support")
-	_ = viper.BindPFlag("DisableMouse", rootCmd.PersistentFlags().Lookup("disable-mouse"))
+	bindFlag("DisableMouse", "disable-mouse")
+
+	rootCmd.PersistentFlags().StringP("clipboard", "", "auto", "clipboard method [auto|system|osc52|tmux|command|internal]")
+	bindFlag("ClipboardMethod", "clipboard")
+	_ = rootCmd.RegisterFlagCompletionFunc("clipboard", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"auto", "system", "osc52", "tmux", "command", "internal"}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	rootCmd.PersistentFlags().BoolP("disable-column-cycle", "", false, "disable column cycling")
-	_ = viper.BindPFlag("DisableColumnCycle", rootCmd.PersistentFlags().Lookup("disable-column-cycle"))
+	bindFlag("DisableColumnCycle", "disable-column-cycle")
+
+	rootCmd.PersistentFlags().StringP("keybind-preset", "", "", "key binding preset [default|emacs|less|vim]")
+	bindFlag("KeyBindPreset", "keybind-preset")
+	_ = rootCmd.RegisterFlagCompletionFunc("keybind-preset", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return oviewer.KeyBindPresets(), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	rootCmd.PersistentFlags().StringP("view-mode", "", "", "apply predefined settings for a specific mode")
-	_ = viper.BindPFlag("ViewMode", rootCmd.PersistentFlags().Lookup("view-mode"))
+	bindFlag("ViewMode", "view-mode")
 
 	rootCmd.PersistentFlags().BoolP("debug", "", false, "debug mode")
-	_ = viper.BindPFlag("Debug", rootCmd.PersistentFlags().Lookup("debug"))
+	bindFlag("Debug", "debug")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -529,12 +617,20 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
+	userFile := ""
 	if err := viper.ReadInConfig(); err != nil {
 		var configNotFoundError viper.ConfigFileNotFoundError
 		if !errors.As(err, &configNotFoundError) || viper.GetBool("debug") {
 			fmt.Fprintln(os.Stderr, "failed to read config file:", err)
 			// If the config file is not found, it is not an error and will continue.
 		}
+	} else {
+		userFile = viper.ConfigFileUsed()
+	}
+
+	// Merge the included files and the per-directory config files.
+	if err := readLayeredConfig(userFile); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to read config file:", err)
 	}
 
 	if err := viper.Unmarshal(&config); err != nil {
@@ -4,9 +4,22 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
+func Test_rootCmdFileArgs(t *testing.T) {
+	// --version returns before the file is opened.
+	rootCmd.SetArgs([]string{"--version", filepath.Join("testdata", "normal.txt")})
+	t.Cleanup(func() {
+		ver = false
+		rootCmd.SetArgs(nil)
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("rootCmd.Execute() error = %v", err)
+	}
+}
+
 func Test_initConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
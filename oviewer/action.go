@@ -55,6 +55,19 @@ func (root *Root) toggleColumnWidth(context.Context) {
 	root.setMessagef("Set ColumnWidth %t", root.Doc.ColumnWidth)
 }
 
+// toggleColumnAlign toggles ColumnAlign each time it is called.
+func (root *Root) toggleColumnAlign(context.Context) {
+	if root.Doc.ColumnAlign {
+		root.Doc.ColumnAlign = false
+	} else {
+		root.Doc.ColumnAlign = true
+		root.Doc.ColumnMode = true
+		root.Doc.ColumnWidth = false
+	}
+	root.Doc.resetAlign()
+	root.setMessagef("Set ColumnAlign %t", root.Doc.ColumnAlign)
+}
+
 // toggleAlternateRows toggles the AlternateRows each time it is called.
 func (root *Root) toggleAlternateRows(context.Context) {
 	root.Doc.AlternateRows = !root.Doc.AlternateRows
@@ -137,7 +150,7 @@ func (root *Root) reload(m *Document) {
 	root.releaseEventBuffer()
 	// Reserve time to read.
 	time.Sleep(100 * time.Millisecond)
-	root.setMessageLogf("reload file %s", root.Doc.FileName)
+	root.setMessageLogf("reload file %s", m.FileName)
 }
 
 // toggleWatch toggles watch mode.
@@ -238,6 +251,49 @@ func (root *Root) goLine(input string) {
 	root.setMessagef("Moved to line %d.%d", lN+1, nTh)
 }
 
+// goColumn moves the column cursor to the column of the name in the header,
+// or of the number (from 1). It turns on the column mode.
+func (root *Root) goColumn(input string) {
+	m := root.Doc
+	if strings.TrimSpace(input) == "" {
+		return
+	}
+	n, ok := m.columnNumber(input)
+	if !ok {
+		root.setMessagef("%s: %s", ErrNoColumn, input)
+		return
+	}
+	d := m.columnLayout().display(n)
+	if d < 0 {
+		root.setMessagef("%s: %s", ErrHiddenColumn, input)
+		return
+	}
+	m.ColumnMode = true
+	m.columnCursor = d
+
+	// Move if off screen
+	x, err := m.optimalX(root.scr, d)
+	if err == nil && (x < m.x || x > m.x+(root.scr.vWidth-root.scr.startX)) {
+		m.x = x
+	}
+	root.setMessagef("Moved to column %d", n+1)
+}
+
+// selectStartColumn selects the column of the Column setting once the header has been read.
+// The derived documents keep their own column.
+func (root *Root) selectStartColumn() {
+	m := root.Doc
+	if m.Column == "" || m.documentType != DocNormal {
+		m.columnSelected = true
+		return
+	}
+	if m.BufEndNum() <= m.firstLine() && !m.BufEOF() {
+		return
+	}
+	m.columnSelected = true
+	root.goColumn(m.Column)
+}
+
 // goLineNumber moves to the specified line number.
 func (root *Root) goLineNumber(lN int) {
 	lN = root.Doc.moveLine(lN - root.Doc.firstLine())
@@ -250,10 +306,12 @@ func (root *Root) markNext(context.Context) {
 		return
 	}
 
-	if len(root.Doc.marked) > root.Doc.markedPoint+1 {
-		root.Doc.markedPoint++
-	} else {
-		root.Doc.markedPoint = 0
+	for i := 0; i < root.repeatCount(); i++ {
+		if len(root.Doc.marked) > root.Doc.markedPoint+1 {
+			root.Doc.markedPoint++
+		} else {
+			root.Doc.markedPoint = 0
+		}
 	}
 	root.goLineNumber(root.Doc.marked[root.Doc.markedPoint])
 }
@@ -264,16 +322,23 @@ func (root *Root) markPrev(context.Context) {
 		return
 	}
 
-	if root.Doc.markedPoint > 0 {
-		root.Doc.markedPoint--
-	} else {
-		root.Doc.markedPoint = len(root.Doc.marked) - 1
+	for i := 0; i < root.repeatCount(); i++ {
+		if root.Doc.markedPoint > 0 {
+			root.Doc.markedPoint--
+		} else {
+			root.Doc.markedPoint = len(root.Doc.marked) - 1
+		}
 	}
 	root.goLineNumber(root.Doc.marked[root.Doc.markedPoint])
 }
 
 // addMark marks the current line number.
+// In visual mode, all selected lines are marked.
 func (root *Root) addMark(context.Context) {
+	if root.visual.active {
+		root.visualMark()
+		return
+	}
 	lN := min(root.Doc.topLN+root.Doc.firstLine(), root.Doc.BufEndNum())
 	root.Doc.marked = remove(root.Doc.marked, lN)
 	root.Doc.marked = append(root.Doc.marked, lN)
@@ -292,6 +357,37 @@ func (root *Root) removeMark(context.Context) {
 	root.setMessagef("Remove the mark at line %d", lN-root.Doc.firstLine()+1)
 }
 
+// letterMark marks the current position with the letter of the key sequence (e.g. "m a").
+func (root *Root) letterMark(context.Context) {
+	letter := root.keyLetter
+	if letter == 0 {
+		root.setMessage("No mark letter")
+		return
+	}
+	m := root.Doc
+	lN := min(m.topLN+m.firstLine(), m.BufEndNum())
+	if m.letterMarks == nil {
+		m.letterMarks = make(map[rune]int)
+	}
+	m.letterMarks[letter] = lN
+	root.setMessagef("Marked '%c' to line %d", letter, lN-m.firstLine()+1)
+}
+
+// goLetterMark moves to the position marked with the letter of the key sequence (e.g. "' a").
+func (root *Root) goLetterMark(context.Context) {
+	letter := root.keyLetter
+	if letter == 0 {
+		root.setMessage("No mark letter")
+		return
+	}
+	lN, ok := root.Doc.letterMarks[letter]
+	if !ok {
+		root.setMessagef("No mark '%c'", letter)
+		return
+	}
+	root.goLineNumber(lN)
+}
+
 // removeAllMark removes all marks.
 func (root *Root) removeAllMark(context.Context) {
 	root.Doc.marked = nil
@@ -312,6 +408,7 @@ func (root *Root) setHeader(input string) {
 
 	root.Doc.Header = num
 	root.Doc.columnWidths = nil
+	root.Doc.resetAlign()
 	root.setMessagef("Set header lines %d", num)
 }
 
@@ -327,6 +424,7 @@ func (root *Root) setSkipLines(input string) {
 	}
 
 	root.Doc.SkipLines = num
+	root.Doc.resetAlign()
 	root.setMessagef("Set skip lines %d", num)
 }
 
@@ -404,8 +502,17 @@ func (root *Root) setViewMode(ctx context.Context, modeName string) {
 	}
 
 	root.Doc.general = mergeGeneral(root.Doc.general, c)
+	root.Doc.modeName = modeName
+	if len(root.Doc.ColumnPositions) > 0 {
+		root.Doc.ColumnWidth = true
+		root.Doc.ColumnMode = true
+	}
+	if root.Doc.ColumnAlign {
+		root.Doc.ColumnMode = true
+	}
 	root.Doc.regexpCompile()
 	root.Doc.ClearCache()
+	root.Doc.resetAlign()
 	root.ViewSync(ctx)
 	// Set caption.
 	if root.Doc.general.Caption != "" {
@@ -698,6 +805,7 @@ func (root *Root) followAll(ctx context.Context) {
 func (root *Root) Cancel(context.Context) {
 	root.General.FollowAll = false
 	root.Doc.FollowMode = false
+	root.cancelJob()
 }
 
 // WriteQuit sets the write flag and executes a quit event.
@@ -549,6 +549,86 @@ func TestRoot_goLine(t *testing.T) {
 	}
 }
 
+func TestRoot_goColumn(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name  string
+		input string
+		hide  []int
+		want  int
+	}{
+		{
+			name:  "name",
+			input: "latency",
+			want:  2,
+		},
+		{
+			name:  "caseInsensitive",
+			input: "TIME",
+			want:  3,
+		},
+		{
+			name:  "number",
+			input: "2",
+			want:  1,
+		},
+		{
+			name:  "hideBefore",
+			input: "latency",
+			hide:  []int{1},
+			want:  1,
+		},
+		{
+			name:  "hidden",
+			input: "latency",
+			hide:  []int{3},
+			want:  0,
+		},
+		{
+			name:  "unknown",
+			input: "nothing",
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+			root.Doc.Header = 1
+			root.Doc.setDelimiter(",")
+			root.Doc.ColumnHide = tt.hide
+			root.goColumn(tt.input)
+			if root.Doc.columnCursor != tt.want {
+				t.Errorf("goColumn() = %v, want %v", root.Doc.columnCursor, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_selectStartColumn(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	root.Doc.Header = 1
+	root.Doc.setDelimiter(",")
+	root.Doc.Column = "time"
+	root.prepareScreen()
+	root.prepareDraw(context.Background())
+	if !root.Doc.ColumnMode || root.Doc.columnCursor != 3 {
+		t.Errorf("selectStartColumn() = %v %v, want true 3", root.Doc.ColumnMode, root.Doc.columnCursor)
+	}
+	// The column is selected only once.
+	root.Doc.columnCursor = 0
+	root.prepareDraw(context.Background())
+	if root.Doc.columnCursor != 0 {
+		t.Errorf("selectStartColumn() selected again")
+	}
+}
+
 func TestRoot_setHeader(t *testing.T) {
 	root := rootHelper(t)
 	root.prepareScreen()
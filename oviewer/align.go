@@ -0,0 +1,172 @@
+package oviewer
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// alignSampleLines is the maximum number of lines sampled from a chunk
+// to compute the widths of the aligned columns.
+const alignSampleLines = 1000
+
+// alignDefaultSeparator is the separator between the aligned columns.
+const alignDefaultSeparator = "  "
+
+// alignEllipsis is the mark of the truncated column.
+const alignEllipsis = "…"
+
+// alignSpace is the padding of the aligned columns.
+var alignSpace = content{
+	mainc: ' ',
+	width: 1,
+	style: tcell.StyleDefault,
+}
+
+// alignKey is the state of the document when the widths of the aligned columns are computed.
+type alignKey struct {
+	endNum   int
+	chunks   int
+	topChunk int
+	tabWidth int
+	maxWidth int
+	widths   int
+}
+
+// isAlign returns true if the delimited columns are aligned.
+// The columns are aligned in the column mode using the delimiter.
+func (m *Document) isAlign() bool {
+	return m.ColumnAlign && m.ColumnMode && !m.ColumnWidth
+}
+
+// resetAlign discards the widths of the aligned columns.
+func (m *Document) resetAlign() {
+	m.alignWidths = nil
+	m.alignKey = alignKey{}
+}
+
+// prepareAlign computes the widths of the aligned columns,
+// when the lines have been read or the chunks have been loaded.
+// The widths are also used to lay out the columns in the ColumnWidth mode.
+func (m *Document) prepareAlign() {
+	if !m.isAlign() && !(m.ColumnWidth && m.isColumnLayout()) {
+		return
+	}
+	chunks := m.alignChunks()
+	topChunk, _ := chunkLineNum(m.topLN + m.firstLine())
+	key := alignKey{
+		endNum:   m.BufEndNum(),
+		chunks:   len(chunks),
+		topChunk: topChunk,
+		tabWidth: m.TabWidth,
+		maxWidth: m.ColumnMaxWidth,
+		widths:   len(m.columnWidths),
+	}
+	if m.alignWidths != nil && key == m.alignKey {
+		return
+	}
+	m.alignKey = key
+	m.alignWidths = m.sampleAlignWidths(chunks)
+}
+
+// alignChunks returns the chunk numbers to sample.
+// They are the first chunk, the loaded chunks and the chunk being displayed.
+func (m *Document) alignChunks() []int {
+	chunks := []int{0}
+	if m.store.loadedChunks != nil {
+		chunks = append(chunks, m.store.loadedChunks.Keys()...)
+	}
+	topChunk, _ := chunkLineNum(m.topLN + m.firstLine())
+	chunks = append(chunks, topChunk)
+	slices.Sort(chunks)
+	return slices.Compact(chunks)
+}
+
+// sampleAlignWidths returns the widths of the columns of the header
+// and the lines sampled from the chunks.
+func (m *Document) sampleAlignWidths(chunks []int) []int {
+	widths := make([]int, 0)
+	endNum := m.BufEndNum()
+	for lN := m.SkipLines; lN < min(m.firstLine(), endNum); lN++ {
+		widths = m.sampleAlignLine(widths, lN)
+	}
+
+	for _, chunkNum := range chunks {
+		start := max(chunkNum*ChunkSize, m.firstLine())
+		end := min((chunkNum+1)*ChunkSize, endNum)
+		step := max((end-start)/alignSampleLines, 1)
+		for lN := start; lN < end; lN += step {
+			widths = m.sampleAlignLine(widths, lN)
+		}
+	}
+	return widths
+}
+
+// sampleAlignLine widens the widths to the columns of the line.
+func (m *Document) sampleAlignLine(widths []int, lN int) []int {
+	str, err := m.sampleLine(lN)
+	if err != nil {
+		return widths
+	}
+	line := m.columnLine(str)
+	ranges, column := m.columnRanges(lN, line)
+	for i, r := range ranges {
+		ks, ke := trimColumn(line.lc, line.pos.x(r[0]), line.pos.x(r[1]))
+		if m.ColumnWidth {
+			// Keep the leading spaces of the right-aligned column.
+			ks = line.pos.x(r[0])
+		}
+		w := ke - ks
+		if limit := m.alignMaxWidth(); limit > 0 && !m.ColumnWidth {
+			w = min(w, limit)
+		}
+		c := column + i
+		for len(widths) <= c {
+			widths = append(widths, 0)
+		}
+		widths[c] = max(widths[c], w)
+	}
+	return widths
+}
+
+// sampleLine returns the line without requesting to load the chunk.
+func (m *Document) sampleLine(lN int) (string, error) {
+	if atomic.LoadInt32(&m.tmpFollow) == 1 {
+		return m.LineStr(lN)
+	}
+	chunkNum, cn := chunkLineNum(lN)
+	b, err := m.store.GetChunkLine(chunkNum, cn)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// alignMaxWidth returns the maximum width of the aligned column.
+// It leaves room for at least one character and the ellipsis.
+func (m *Document) alignMaxWidth() int {
+	if m.ColumnMaxWidth <= 0 {
+		return 0
+	}
+	return max(m.ColumnMaxWidth, 2)
+}
+
+// alignWidth returns the width of the aligned column.
+func (m *Document) alignWidth(c int) int {
+	if c < len(m.alignWidths) {
+		return m.alignWidths[c]
+	}
+	return 0
+}
+
+// trimColumn returns the range of the contents of the column without the surrounding spaces.
+func trimColumn(lc contents, start int, end int) (int, int) {
+	for start < end && lc[start].mainc == ' ' {
+		start++
+	}
+	for end > start && lc[end-1].mainc == ' ' {
+		end--
+	}
+	return start, end
+}
@@ -0,0 +1,143 @@
+package oviewer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocument_columnFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		str        string
+		delimiter  string
+		want       [][2]int
+		wantColumn int
+	}{
+		{
+			name:      "comma",
+			str:       "a,bb,c",
+			delimiter: ",",
+			want:      [][2]int{{0, 1}, {2, 4}, {5, 6}},
+		},
+		{
+			name:      "fence",
+			str:       "| a | b |",
+			delimiter: "|",
+			want:      [][2]int{{1, 4}, {5, 8}},
+		},
+		{
+			name:      "noDelimiter",
+			str:       "abc",
+			delimiter: ",",
+			want:      [][2]int{{0, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, tt.str)
+			m.setDelimiter(tt.delimiter)
+			got, gotColumn := m.columnFields(0, tt.str)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.columnFields() got = %v, want %v", got, tt.want)
+			}
+			if gotColumn != tt.wantColumn {
+				t.Errorf("Document.columnFields() column = %v, want %v", gotColumn, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestDocument_alignLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		str       string
+		separator string
+		maxWidth  int
+		lN        int
+		want      string
+	}{
+		{
+			name: "header",
+			str:  "name,size,type\nfoo,1,x\nlongname,12345,y\n",
+			lN:   0,
+			want: "name      size   type",
+		},
+		{
+			name: "body",
+			str:  "name,size,type\nfoo,1,x\nlongname,12345,y\n",
+			lN:   1,
+			want: "foo       1      x",
+		},
+		{
+			name:      "separator",
+			str:       "name,size,type\nfoo,1,x\nlongname,12345,y\n",
+			separator: " | ",
+			lN:        1,
+			want:      "foo      | 1     | x",
+		},
+		{
+			name:     "maxWidth",
+			str:      "name,size,type\nfoo,1,x\nlongname,12345,y\n",
+			maxWidth: 5,
+			lN:       2,
+			want:     "long…  12345  y",
+		},
+		{
+			name: "trim",
+			str:  "a , b\nccc, d\n",
+			lN:   0,
+			want: "a    b",
+		},
+		{
+			name: "wide",
+			str:  "あいう,b\nc,d\n",
+			lN:   1,
+			want: "c       d",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, tt.str)
+			m.setDelimiter(",")
+			m.ColumnMode = true
+			m.ColumnAlign = true
+			m.ColumnAlignSeparator = tt.separator
+			m.ColumnMaxWidth = tt.maxWidth
+			m.prepareAlign()
+			line := m.getLineC(tt.lN, m.TabWidth)
+			got, _ := ContentsToStr(line.lc)
+			if got != tt.want {
+				t.Errorf("Document.alignLine() = %q, want %q", got, tt.want)
+			}
+			// The original string is kept for search and save.
+			org, _ := m.LineStr(tt.lN)
+			if line.str != org {
+				t.Errorf("Document.alignLine() str = %q, want %q", line.str, org)
+			}
+			for i := 1; i < len(line.pos); i++ {
+				if line.pos[i] < line.pos[i-1] {
+					t.Fatalf("Document.alignLine() pos is not monotonic %v", line.pos)
+				}
+			}
+			if line.pos.x(len(line.str)) != len(line.lc) {
+				t.Errorf("Document.alignLine() pos end = %d, want %d", line.pos.x(len(line.str)), len(line.lc))
+			}
+		})
+	}
+}
+
+func TestDocument_alignLineColumn(t *testing.T) {
+	m := docHelper(t, "name,size\nfoo,1\nlongname,12345\n")
+	m.setDelimiter(",")
+	m.ColumnMode = true
+	m.ColumnAlign = true
+	m.prepareAlign()
+	line := m.getLineC(1, m.TabWidth)
+	// "foo       1"
+	if got := line.pos.x(4); got != 10 {
+		t.Errorf("Document.alignLine() x of the second column = %d, want 10", got)
+	}
+	if got := line.pos.x(3); got != 8 {
+		t.Errorf("Document.alignLine() x of the delimiter = %d, want 8", got)
+	}
+}
@@ -0,0 +1,155 @@
+package oviewer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// The clipboard method specified by ClipboardMethod.
+const (
+	// clipboardAuto tries the system clipboard, tmux and OSC 52 in order.
+	clipboardAuto = "auto"
+	// clipboardSystem uses the system clipboard (xclip/xsel/wl-copy/pbcopy...).
+	clipboardSystem = "system"
+	// clipboardOSC52 sends the OSC 52 escape sequence to the terminal.
+	clipboardOSC52 = "osc52"
+	// clipboardTmux uses the tmux paste buffer.
+	clipboardTmux = "tmux"
+	// clipboardCommand runs ClipboardCopyCommand and ClipboardPasteCommand.
+	clipboardCommand = "command"
+	// clipboardInternal only uses the internal register of ov.
+	clipboardInternal = "internal"
+)
+
+// writeClipboard writes str to the clipboard with the configured method.
+// It returns the name of the method actually used.
+// The string is always kept in the internal register, so that it can be pasted in ov.
+func (root *Root) writeClipboard(str string) (string, error) {
+	root.clipboardRegister = str
+
+	method := strings.ToLower(root.Config.ClipboardMethod)
+	if method != "" && method != clipboardAuto {
+		return method, root.writeClipboardMethod(method, str)
+	}
+
+	methods := []string{clipboardSystem}
+	if os.Getenv("TMUX") != "" {
+		methods = append(methods, clipboardTmux)
+	}
+	methods = append(methods, clipboardOSC52)
+	var err error
+	for _, method := range methods {
+		if err = root.writeClipboardMethod(method, str); err == nil {
+			return method, nil
+		}
+		root.debugMessage(fmt.Sprintf("clipboard %s: %s", method, err))
+	}
+	return clipboardInternal, err
+}
+
+// copyClipboard writes str to the clipboard and shows the result in the status line.
+// The method used is added to msg, and the error is shown instead if it fails.
+func (root *Root) copyClipboard(str string, msg string) {
+	method, err := root.writeClipboard(str)
+	if err != nil {
+		root.setMessageLogf("cannot copy (%s): %s", method, err)
+		return
+	}
+	root.setMessagef("%s (%s)", msg, method)
+}
+
+// writeClipboardMethod writes str with the specified method.
+func (root *Root) writeClipboardMethod(method string, str string) error {
+	switch method {
+	case clipboardSystem:
+		return clipboard.WriteAll(str)
+	case clipboardOSC52:
+		return root.writeOSC52(str)
+	case clipboardTmux:
+		return runClipboardCopy([]string{"tmux", "load-buffer", "-"}, str)
+	case clipboardCommand:
+		return runClipboardCopy(root.Config.ClipboardCopyCommand, str)
+	case clipboardInternal:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownClipboard, method)
+}
+
+// readClipboard reads a string from the clipboard with the configured method.
+// OSC 52 cannot be read back, so the internal register is used instead.
+func (root *Root) readClipboard() (string, error) {
+	method := strings.ToLower(root.Config.ClipboardMethod)
+	switch method {
+	case clipboardSystem:
+		return clipboard.ReadAll()
+	case clipboardTmux:
+		return runClipboardPaste([]string{"tmux", "save-buffer", "-"})
+	case clipboardCommand:
+		return runClipboardPaste(root.Config.ClipboardPasteCommand)
+	case clipboardOSC52, clipboardInternal:
+		return root.clipboardRegister, nil
+	case "", clipboardAuto:
+		if str, err := clipboard.ReadAll(); err == nil {
+			return str, nil
+		}
+		if os.Getenv("TMUX") != "" {
+			if str, err := runClipboardPaste([]string{"tmux", "save-buffer", "-"}); err == nil {
+				return str, nil
+			}
+		}
+		return root.clipboardRegister, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownClipboard, method)
+}
+
+// writeOSC52 sends the OSC 52 escape sequence to the terminal.
+func (root *Root) writeOSC52(str string) error {
+	tty, ok := root.Screen.Tty()
+	if !ok || tty == nil {
+		return ErrNoTty
+	}
+	_, err := tty.Write([]byte(osc52(str, os.Getenv("TMUX") != "")))
+	return err
+}
+
+// osc52 returns the OSC 52 escape sequence that sets str to the clipboard.
+// In tmux, the sequence is wrapped in the passthrough sequence.
+func osc52(str string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(str)) + "\x07"
+	if !tmux {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// runClipboardCopy runs the command with str as the standard input.
+func runClipboardCopy(args []string, str string) error {
+	if len(args) == 0 {
+		return ErrNoClipboardCommand
+	}
+	//nolint:gosec
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdin = strings.NewReader(str)
+	return c.Run()
+}
+
+// runClipboardPaste runs the command and returns the standard output.
+func runClipboardPaste(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", ErrNoClipboardCommand
+	}
+	//nolint:gosec
+	c := exec.Command(args[0], args[1:]...)
+	var out bytes.Buffer
+	c.Stdout = &out
+	if err := c.Run(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
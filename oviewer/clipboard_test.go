@@ -0,0 +1,148 @@
+package oviewer
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_osc52(t *testing.T) {
+	type args struct {
+		str  string
+		tmux bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "test1",
+			args: args{str: "test", tmux: false},
+			want: "\x1b]52;c;dGVzdA==\x07",
+		},
+		{
+			name: "testTmux",
+			args: args{str: "test", tmux: true},
+			want: "\x1bPtmux;\x1b\x1b]52;c;dGVzdA==\x07\x1b\\",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := osc52(tt.args.str, tt.args.tmux); got != tt.want {
+				t.Errorf("osc52() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_writeClipboard(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name       string
+		method     string
+		copyCmd    []string
+		pasteCmd   []string
+		str        string
+		wantMethod string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "testInternal",
+			method:     "internal",
+			str:        "test",
+			wantMethod: "internal",
+			want:       "test",
+		},
+		{
+			name:       "testCommand",
+			method:     "command",
+			copyCmd:    []string{"cat"},
+			pasteCmd:   []string{"echo", "-n", "paste"},
+			str:        "test",
+			wantMethod: "command",
+			want:       "paste",
+		},
+		{
+			name:       "testNoCommand",
+			method:     "command",
+			str:        "test",
+			wantMethod: "command",
+			wantErr:    true,
+		},
+		{
+			name:       "testOSC52NoTty",
+			method:     "osc52",
+			str:        "test",
+			wantMethod: "osc52",
+			want:       "test",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootHelper(t)
+			root.Config.ClipboardMethod = tt.method
+			root.Config.ClipboardCopyCommand = tt.copyCmd
+			root.Config.ClipboardPasteCommand = tt.pasteCmd
+			method, err := root.writeClipboard(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Root.writeClipboard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if method != tt.wantMethod {
+				t.Errorf("Root.writeClipboard() = %v, want %v", method, tt.wantMethod)
+			}
+			if tt.wantErr && tt.method == "command" {
+				return
+			}
+			got, err := root.readClipboard()
+			if err != nil {
+				t.Fatalf("Root.readClipboard() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Root.readClipboard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_copyClipboard(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name    string
+		method  string
+		copyCmd []string
+		want    string
+	}{
+		{
+			name:    "testCopy",
+			method:  "command",
+			copyCmd: []string{"cat"},
+			want:    "Copy (command)",
+		},
+		{
+			name:    "testCopyError",
+			method:  "command",
+			copyCmd: []string{"false"},
+			want:    "cannot copy (command): exit status 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootHelper(t)
+			root.Config.ClipboardMethod = tt.method
+			root.Config.ClipboardCopyCommand = tt.copyCmd
+			root.copyClipboard("test", "Copy")
+			if root.message != tt.want {
+				t.Errorf("Root.copyClipboard() message = %v, want %v", root.message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// commandNames is the list of commands of the command line.
+var commandNames = []string{
+	"close",
+	"e",
+	"edit",
+	"filter",
+	"mode",
+	"n",
+	"next",
+	"p",
+	"prev",
+	"q",
+	"quit",
+	"set",
+	"w",
+	"write",
+}
+
+// generalSetters is the setter of the general field that has its own input mode.
+// The value is checked and applied in the same way as the input mode.
+func (root *Root) generalSetters() map[string]func(string) {
+	return map[string]func(string){
+		"ColumnDelimiter":      root.setDelimiter,
+		"Header":               root.setHeader,
+		"JumpTarget":           root.setJumpTarget,
+		"MultiColorWords":      root.setMultiColor,
+		"SectionDelimiter":     root.setSectionDelimiter,
+		"SectionHeaderNum":     root.setSectionNum,
+		"SectionStartPosition": root.setSectionStart,
+		"SkipLines":            root.setSkipLines,
+		"TabWidth":             root.setTabWidth,
+		"WatchInterval":        root.setWatchInterval,
+	}
+}
+
+// optionToggles is the toggle action of the boolean field.
+func (root *Root) optionToggles() map[string]func(context.Context) {
+	return map[string]func(context.Context){
+		"AlternateRows":    root.toggleAlternateRows,
+		"DisableMouse":     root.toggleMouse,
+		"ColumnAlign":      root.toggleColumnAlign,
+		"ColumnMode":       root.toggleColumnMode,
+		"ColumnRainbow":    root.toggleRainbow,
+		"ColumnWidth":      root.toggleColumnWidth,
+		"FollowAll":        root.toggleFollowAll,
+		"FollowMode":       root.toggleFollowMode,
+		"FollowSection":    root.toggleFollowSection,
+		"HideOtherSection": root.toggleHideOtherSection,
+		"LineNumMode":      root.toggleLineNumMode,
+		"PlainMode":        root.togglePlain,
+		"WrapMode":         root.toggleWrapMode,
+	}
+}
+
+// commandIgnoreFields are the fields that cannot be set from the command line.
+var commandIgnoreFields = map[string]bool{
+	"ColumnDelimiterReg":  true,
+	"SectionDelimiterReg": true,
+	"HScrollWidthNum":     true,
+	"General":             true,
+	"Mode":                true,
+	"Keybind":             true,
+}
+
+// optionField represents a field that can be set by the set command.
+type optionField struct {
+	name    string
+	value   reflect.Value
+	general bool
+}
+
+// optionFields returns the settable fields of the general of the document and Config.
+func (root *Root) optionFields() []optionField {
+	var fields []optionField
+	fields = append(fields, settableFields(reflect.ValueOf(&root.Doc.general).Elem(), true)...)
+	fields = append(fields, settableFields(reflect.ValueOf(&root.Config).Elem(), false)...)
+	return fields
+}
+
+// ovStyleType is the type of OVStyle.
+var ovStyleType = reflect.TypeOf(OVStyle{})
+
+// settableFields returns the fields of string, int, bool, []string, OVStyle and []OVStyle type.
+func settableFields(v reflect.Value, general bool) []optionField {
+	var fields []optionField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || commandIgnoreFields[f.Name] {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.String, reflect.Int, reflect.Bool:
+		case reflect.Struct:
+			if f.Type != ovStyleType {
+				continue
+			}
+		case reflect.Slice:
+			if f.Type.Elem().Kind() != reflect.String && f.Type.Elem() != ovStyleType {
+				continue
+			}
+		default:
+			continue
+		}
+		fields = append(fields, optionField{name: f.Name, value: v.Field(i), general: general})
+	}
+	return fields
+}
+
+// optionField returns the field of the option name.
+// The name is case-insensitive and "Mode" can be omitted (e.g. wrap = WrapMode).
+func (root *Root) optionField(name string) (optionField, bool) {
+	for _, f := range root.optionFields() {
+		if strings.EqualFold(f.name, name) || strings.EqualFold(f.name, name+"Mode") {
+			return f, true
+		}
+	}
+	return optionField{}, false
+}
+
+// runCommand runs the command line.
+func (root *Root) runCommand(ctx context.Context, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	// :number moves to the line number.
+	if _, err := strconv.Atoi(line); err == nil {
+		root.goLine(line)
+		return
+	}
+
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch cmd {
+	case "set":
+		root.setCommand(ctx, arg)
+	case "mode":
+		root.setViewMode(ctx, arg)
+	case "e", "edit":
+		root.editCommand(ctx, arg)
+	case "w", "write":
+		root.writeCommand(arg)
+	case "filter":
+		root.filter(ctx, arg)
+	case "n", "next":
+		root.nextDoc(ctx)
+	case "p", "prev":
+		root.previousDoc(ctx)
+	case "close":
+		root.closeDocument(ctx)
+	case "q", "quit":
+		root.Quit(ctx)
+	default:
+		root.setMessagef("unknown command: %s", cmd)
+	}
+}
+
+// setCommand sets the option.
+//
+//	set name=value
+//	set name     (true for bool)
+//	set noname   (false for bool)
+//	set name!    (toggle for bool)
+//	set name?    (display the value)
+func (root *Root) setCommand(ctx context.Context, arg string) {
+	if arg == "" {
+		root.setMessage("set: no option")
+		return
+	}
+	name, value, hasValue := strings.Cut(arg, "=")
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+
+	show, toggle, negate := false, false, false
+	switch {
+	case strings.HasSuffix(name, "?"):
+		name, show = strings.TrimSuffix(name, "?"), true
+	case strings.HasSuffix(name, "!"):
+		name, toggle = strings.TrimSuffix(name, "!"), true
+	}
+
+	f, ok := root.optionField(name)
+	if !ok && strings.HasPrefix(name, "no") {
+		if f, ok = root.optionField(strings.TrimPrefix(name, "no")); ok {
+			negate = true
+		}
+	}
+	if !ok {
+		root.setMessagef("set: unknown option %s", name)
+		return
+	}
+
+	if show {
+		root.setMessagef("%s=%s", f.name, optionString(f.value))
+		return
+	}
+
+	if f.value.Kind() == reflect.Bool && !hasValue {
+		b := !negate
+		if toggle {
+			b = !f.value.Bool()
+		}
+		value = strconv.FormatBool(b)
+	} else if !hasValue {
+		root.setMessagef("set: %s requires a value", f.name)
+		return
+	}
+
+	if err := root.setOption(ctx, f, value); err != nil {
+		root.setMessagef("set: %s: %s", f.name, err)
+	}
+}
+
+// setOption sets the value of the option and reflects it in the display.
+func (root *Root) setOption(ctx context.Context, f optionField, value string) error {
+	if setter, ok := root.generalSetters()[f.name]; ok {
+		setter(value)
+		return nil
+	}
+	if toggle, ok := root.optionToggles()[f.name]; ok {
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		if f.value.Bool() != b {
+			toggle(ctx)
+		}
+		return nil
+	}
+
+	if err := setFieldValue(f.value, value); err != nil {
+		return err
+	}
+	if f.general {
+		if f.name == "Caption" {
+			root.Doc.Caption = value
+		}
+		root.Doc.ClearCache()
+		root.ViewSync(ctx)
+	}
+	root.setMessagef("Set %s=%s", f.name, optionString(f.value))
+	return nil
+}
+
+// setFieldValue converts the string and sets it in the field.
+func setFieldValue(v reflect.Value, value string) error {
+	if v.Type() == ovStyleType || (v.Kind() == reflect.Slice && v.Type().Elem() == ovStyleType) {
+		return setStyleValue(v, value)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return ErrInvalidNumber
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(strings.Fields(value)))
+	}
+	return nil
+}
+
+// setStyleValue sets the style written in the YAML flow style of the config file.
+//
+//	{Foreground: red, Bold: true}
+//	[{Foreground: red}, {Foreground: blue}]
+func setStyleValue(v reflect.Value, value string) error {
+	var data any
+	if err := yaml.Unmarshal([]byte(value), &data); err != nil {
+		return fmt.Errorf("invalid style %s", value)
+	}
+	if v.Kind() != reflect.Slice {
+		s, err := parseOVStyle(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(s))
+		return nil
+	}
+	list, ok := data.([]any)
+	if !ok {
+		return fmt.Errorf("invalid style list %s", value)
+	}
+	// The styles are used in rotation, so at least one is needed.
+	if len(list) == 0 {
+		return fmt.Errorf("empty style list %s", value)
+	}
+	styles := make([]OVStyle, 0, len(list))
+	for _, d := range list {
+		s, err := parseOVStyle(d)
+		if err != nil {
+			return err
+		}
+		styles = append(styles, s)
+	}
+	v.Set(reflect.ValueOf(styles))
+	return nil
+}
+
+// parseOVStyle returns OVStyle from the map of the field names (case-insensitive) and values.
+func parseOVStyle(data any) (OVStyle, error) {
+	var s OVStyle
+	m, ok := data.(map[string]any)
+	if !ok {
+		return s, fmt.Errorf("invalid style %v", data)
+	}
+	sv := reflect.ValueOf(&s).Elem()
+	for key, value := range m {
+		f := sv.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, key)
+		})
+		if !f.IsValid() {
+			return s, fmt.Errorf("unknown style field %s", key)
+		}
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(fmt.Sprint(value))
+		case reflect.Bool:
+			b, ok := value.(bool)
+			if !ok {
+				return s, fmt.Errorf("invalid bool value %v", value)
+			}
+			f.SetBool(b)
+		}
+	}
+	return s, nil
+}
+
+// optionString returns the value of the option as a string.
+// The style is returned in the same form as setStyleValue.
+func optionString(v reflect.Value) string {
+	switch {
+	case v.Type() == ovStyleType:
+		return styleString(v.Interface().(OVStyle))
+	case v.Kind() == reflect.Slice && v.Type().Elem() == ovStyleType:
+		styles := v.Interface().([]OVStyle)
+		strs := make([]string, 0, len(styles))
+		for _, s := range styles {
+			strs = append(strs, styleString(s))
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// styleString returns the fields of the style that are set.
+func styleString(s OVStyle) string {
+	sv := reflect.ValueOf(s)
+	var fields []string
+	for i := 0; i < sv.NumField(); i++ {
+		if sv.Field(i).IsZero() {
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("%s: %v", sv.Type().Field(i).Name, sv.Field(i).Interface()))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// parseBool parses a boolean value including on/off.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid bool value %s", value)
+	}
+	return b, nil
+}
+
+// editCommand opens the file as a new document.
+func (root *Root) editCommand(ctx context.Context, fileName string) {
+	if fileName == "" {
+		root.setMessage("edit: no file name")
+		return
+	}
+	m, err := OpenDocument(fileName)
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	root.addDocument(ctx, m)
+}
+
+// writeCommand saves the buffer to the file.
+func (root *Root) writeCommand(fileName string) {
+	if fileName == "" {
+		root.setMessage("write: no file name")
+		return
+	}
+	if root.Doc.seekable && !root.visual.active {
+		root.setMessage("Does not support saving regular files")
+		return
+	}
+	root.saveBuffer(fileName)
+}
+
+// commandComplete returns the completion candidates of the command line.
+func (root *Root) commandComplete(str string) []string {
+	cmd, arg, found := strings.Cut(str, " ")
+	if !found {
+		return prefixMatch(commandNames, cmd, "", " ")
+	}
+
+	head := cmd + " "
+	arg = strings.TrimLeft(arg, " ")
+	switch cmd {
+	case "set":
+		return root.setComplete(head, arg)
+	case "mode":
+		return prefixMatch(root.modeNames(), arg, head, "")
+	case "e", "edit", "w", "write":
+		return fileComplete(head, arg)
+	}
+	return nil
+}
+
+// setComplete completes the option names and values of the set command.
+func (root *Root) setComplete(head string, arg string) []string {
+	name, value, found := strings.Cut(arg, "=")
+	if !found {
+		var list []string
+		for _, f := range root.optionFields() {
+			suffix := "="
+			if f.value.Kind() == reflect.Bool {
+				suffix = ""
+			}
+			list = append(list, f.name+suffix)
+		}
+		return prefixMatch(list, name, head, "")
+	}
+
+	f, ok := root.optionField(name)
+	if !ok {
+		return nil
+	}
+	head = head + name + "="
+	switch f.value.Kind() {
+	case reflect.Bool:
+		return prefixMatch([]string{"true", "false"}, value, head, "")
+	}
+	switch f.name {
+	case "ColumnDelimiter":
+		return prefixMatch(delimiterCandidate().list, value, head, "")
+	case "JumpTarget":
+		return prefixMatch(jumpTargetCandidate().list, value, head, "")
+	case "SectionDelimiter":
+		return prefixMatch(sectionDelimiterCandidate().list, value, head, "")
+	case "ClipboardMethod":
+		return prefixMatch([]string{clipboardAuto, clipboardSystem, clipboardOSC52, clipboardTmux, clipboardCommand, clipboardInternal}, value, head, "")
+	case "KeyBindPreset":
+		return prefixMatch(KeyBindPresets(), value, head, "")
+	case "ViewMode":
+		return prefixMatch(root.modeNames(), value, head, "")
+	}
+	// The current value is a candidate.
+	return prefixMatch([]string{optionString(f.value)}, value, head, "")
+}
+
+// modeNames returns the names of the view modes.
+func (root *Root) modeNames() []string {
+	names := make([]string, 0, len(root.Config.Mode)+1)
+	names = append(names, "general")
+	for name := range root.Config.Mode {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// fileComplete completes the file name.
+func fileComplete(head string, arg string) []string {
+	matches, err := filepath.Glob(arg + "*")
+	if err != nil {
+		return nil
+	}
+	list := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if fi, err := os.Stat(m); err == nil && fi.IsDir() {
+			m += string(filepath.Separator)
+		}
+		list = append(list, head+m)
+	}
+	return list
+}
+
+// prefixMatch returns the list items that start with prefix (case-insensitive),
+// with head and suffix added.
+func prefixMatch(list []string, prefix string, head string, suffix string) []string {
+	var matches []string
+	for _, s := range list {
+		if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+			matches = append(matches, head+s+suffix)
+		}
+	}
+	return matches
+}
+
+// commonPrefix returns the longest common prefix of the strings.
+func commonPrefix(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	prefix := list[0]
+	for _, s := range list[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
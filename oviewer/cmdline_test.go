@@ -0,0 +1,151 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_runCommand(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.prepareScreen()
+	ctx := context.Background()
+
+	root.runCommand(ctx, "set header=2")
+	if root.Doc.Header != 2 {
+		t.Errorf("Header = %v, want %v", root.Doc.Header, 2)
+	}
+	wrap := root.Doc.WrapMode
+	root.runCommand(ctx, "set wrap!")
+	if root.Doc.WrapMode == wrap {
+		t.Errorf("WrapMode = %v, want %v", root.Doc.WrapMode, !wrap)
+	}
+	root.runCommand(ctx, "set nowrap")
+	if root.Doc.WrapMode {
+		t.Errorf("WrapMode = %v, want %v", root.Doc.WrapMode, false)
+	}
+	root.runCommand(ctx, "set MarkStyleWidth=3")
+	if root.Doc.MarkStyleWidth != 3 {
+		t.Errorf("MarkStyleWidth = %v, want %v", root.Doc.MarkStyleWidth, 3)
+	}
+	root.runCommand(ctx, "set hscrollwidth=20%")
+	if root.Doc.HScrollWidth != "20%" {
+		t.Errorf("HScrollWidth = %v, want %v", root.Doc.HScrollWidth, "20%")
+	}
+	root.runCommand(ctx, "set casesensitive")
+	if !root.Config.CaseSensitive {
+		t.Errorf("CaseSensitive = %v, want %v", root.Config.CaseSensitive, true)
+	}
+	root.runCommand(ctx, "set header?")
+	if root.message != "Header=2" {
+		t.Errorf("message = %v, want %v", root.message, "Header=2")
+	}
+	root.runCommand(ctx, "set unknown=1")
+	if root.message != "set: unknown option unknown" {
+		t.Errorf("message = %v", root.message)
+	}
+	root.runCommand(ctx, "set TabWidth=x")
+	if root.Doc.TabWidth != 8 {
+		t.Errorf("TabWidth = %v, want %v", root.Doc.TabWidth, 8)
+	}
+	root.runCommand(ctx, "set StyleHeader={Foreground: red, Bold: true}")
+	if want := (OVStyle{Foreground: "red", Bold: true}); root.StyleHeader != want {
+		t.Errorf("StyleHeader = %v, want %v", root.StyleHeader, want)
+	}
+	root.runCommand(ctx, "set stylecolumnrainbow=[{foreground: red}, {Foreground: blue, Underline: true}]")
+	if want := []OVStyle{{Foreground: "red"}, {Foreground: "blue", Underline: true}}; !reflect.DeepEqual(root.StyleColumnRainbow, want) {
+		t.Errorf("StyleColumnRainbow = %v, want %v", root.StyleColumnRainbow, want)
+	}
+	root.runCommand(ctx, "set StyleColumnRainbow?")
+	if want := "StyleColumnRainbow=[{Foreground: red}, {Foreground: blue, Underline: true}]"; root.message != want {
+		t.Errorf("message = %v, want %v", root.message, want)
+	}
+	for _, cmd := range []string{"set StyleColumnRainbow=[]", "set StyleMultiColorHighlight=[]"} {
+		root.runCommand(ctx, cmd)
+		if len(root.StyleColumnRainbow) == 0 || len(root.StyleMultiColorHighlight) == 0 {
+			t.Errorf("%s: the empty style list is set", cmd)
+		}
+	}
+	root.runCommand(ctx, "set StyleHeader={Color: red}")
+	if want := (OVStyle{Foreground: "red", Bold: true}); root.StyleHeader != want {
+		t.Errorf("StyleHeader = %v, want %v", root.StyleHeader, want)
+	}
+	root.runCommand(ctx, "10")
+	if root.Doc.topLN != 9 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 9)
+	}
+}
+
+func TestRoot_commandComplete(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name string
+		str  string
+		want []string
+	}{
+		{
+			name: "testCommand",
+			str:  "fi",
+			want: []string{"filter "},
+		},
+		{
+			name: "testOption",
+			str:  "set head",
+			want: []string{"set Header="},
+		},
+		{
+			name: "testBoolOption",
+			str:  "set WrapM",
+			want: []string{"set WrapMode"},
+		},
+		{
+			name: "testBoolValue",
+			str:  "set wrap=t",
+			want: []string{"set wrap=true"},
+		},
+		{
+			name: "testMode",
+			str:  "mode gen",
+			want: []string{"mode general"},
+		},
+		{
+			name: "testDelimiter",
+			str:  "set ColumnDelimiter=|",
+			want: []string{"set ColumnDelimiter=|"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootHelper(t)
+			if got := root.commandComplete(tt.str); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Root.commandComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_eventCommand_Complete(t *testing.T) {
+	complete := func(string) []string {
+		return []string{"set Header=", "set HideOtherSection", "set HScrollWidth="}
+	}
+	e := newCommandEvent(blankCandidate(), complete)
+	if got := e.Complete("set H"); got != "set Header=" {
+		t.Errorf("eventCommand.Complete() = %v, want %v", got, "set Header=")
+	}
+	if got := e.Complete("set Header="); got != "set HideOtherSection" {
+		t.Errorf("eventCommand.Complete() = %v, want %v", got, "set HideOtherSection")
+	}
+	if got := e.Complete("set HideOtherSection"); got != "set HScrollWidth=" {
+		t.Errorf("eventCommand.Complete() = %v, want %v", got, "set HScrollWidth=")
+	}
+}
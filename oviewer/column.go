@@ -0,0 +1,283 @@
+package oviewer
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// columnFields returns the byte ranges of the fields of the line
+// and the column number of the first field.
+// The leftmost and rightmost fences are not fields (see delimiterWidths).
+func (m *Document) columnFields(lN int, str string) ([][2]int, int) {
+	indexes, column := m.columnIndexes(lN, str)
+	return fieldRanges(str, indexes, column), column
+}
+
+// fieldRanges returns the byte ranges of the fields from the positions of the delimiters.
+func fieldRanges(str string, indexes [][]int, column int) [][2]int {
+	if len(indexes) == 0 {
+		return [][2]int{{0, len(str)}}
+	}
+
+	fields := make([][2]int, 0, len(indexes)+1)
+	start := 0
+	if column == 0 && indexes[0][0] == 0 {
+		start = indexes[0][1]
+		indexes = indexes[1:]
+	}
+	for _, idx := range indexes {
+		fields = append(fields, [2]int{start, idx[0]})
+		start = idx[1]
+	}
+	if start < len(str) || len(fields) == 0 {
+		fields = append(fields, [2]int{start, len(str)})
+	}
+	return fields
+}
+
+// columnRanges returns the byte ranges of the columns of the line
+// and the column number of the first column.
+// The columns are separated by the delimiter, or by columnWidths in the ColumnWidth mode.
+func (m *Document) columnRanges(lN int, line LineC) ([][2]int, int) {
+	if !m.ColumnWidth {
+		return m.columnFields(lN, line.str)
+	}
+	if len(m.columnWidths) == 0 {
+		return [][2]int{{0, len(line.str)}}, 0
+	}
+
+	xRanges := m.columnWidthRanges(line.lc)
+	ranges := make([][2]int, 0, len(xRanges))
+	for _, r := range xRanges {
+		bs := line.pos.byteAt(r[0])
+		ranges = append(ranges, [2]int{bs, max(line.pos.byteAt(r[1]), bs)})
+	}
+	return ranges, 0
+}
+
+// columnWidthRanges returns the x ranges of the columns of the contents in the ColumnWidth mode.
+// The guessed boundaries are separators that move to a nearby blank (see findColumnEnd).
+// The boundaries of ColumnPositions are used as they are, and the columns are adjacent.
+func (m *Document) columnWidthRanges(lc contents) [][2]int {
+	ranges := make([][2]int, 0, len(m.columnWidths)+1)
+	start, end := -1, -1
+	for c := 0; c < len(m.columnWidths)+1; c++ {
+		if len(m.ColumnPositions) == 0 {
+			start = end + 1
+			end = findColumnEnd(lc, m.columnWidths, c)
+		} else {
+			start = max(end, 0)
+			end = len(lc)
+			if c < len(m.columnWidths) {
+				end = max(min(m.columnWidths[c]+1, len(lc)), start)
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// byteAt returns the byte position of the string from the x position of the contents.
+func (pos widthPos) byteAt(x int) int {
+	return min(sort.SearchInts(pos, x), len(pos)-1)
+}
+
+// columnLine returns the line for the column operations.
+// Unlike getLineC, the line is not cached and the columns are not aligned.
+func (m *Document) columnLine(str string) LineC {
+	lc := parseString(str, m.TabWidth)
+	s, pos := ContentsToStr(lc)
+	return LineC{
+		lc:    lc,
+		str:   s,
+		pos:   pos,
+		valid: true,
+	}
+}
+
+// columnValue returns the value of the column n of the line.
+// The surrounding spaces are removed, and the quotes are removed in CSV.
+// It returns false if the line does not have the column.
+func (m *Document) columnValue(lN int, line LineC, n int) (string, bool) {
+	ranges, column := m.columnRanges(lN, line)
+	i := n - column
+	if i < 0 || i >= len(ranges) {
+		return "", false
+	}
+	r := ranges[i]
+	value := strings.TrimSpace(line.str[r[0]:r[1]])
+	if !m.ColumnWidth && m.isCSV() {
+		value = csvUnquote(value)
+	}
+	return value, true
+}
+
+// columnValueOf returns the value of the column n of the line string
+// and its byte range without the surrounding spaces.
+// Unlike columnValue, the line is assumed to start at the beginning of a row.
+func (m *Document) columnValueOf(str string, n int) (string, [2]int, bool) {
+	str, ranges := m.rowRanges(str)
+	if n < 0 || n >= len(ranges) {
+		return "", [2]int{}, false
+	}
+	value, r := m.rowValue(str, ranges[n])
+	return value, r, true
+}
+
+// rowValues returns the values of all columns of the row string.
+func (m *Document) rowValues(str string) []string {
+	str, ranges := m.rowRanges(str)
+	values := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		value, _ := m.rowValue(str, r)
+		values = append(values, value)
+	}
+	return values
+}
+
+// rowRanges returns the byte ranges of the columns of the row string.
+// The returned string is the string the ranges point to.
+func (m *Document) rowRanges(str string) (string, [][2]int) {
+	switch {
+	case m.ColumnWidth:
+		line := m.columnLine(str)
+		ranges, _ := m.columnRanges(0, line)
+		return line.str, ranges
+	case m.isCSV():
+		indexes, _ := csvIndexes(str, m.ColumnDelimiter, m.ColumnDelimiterReg, false)
+		return str, fieldRanges(str, indexes, 0)
+	default:
+		return str, fieldRanges(str, allIndex(str, m.ColumnDelimiter, m.ColumnDelimiterReg), 0)
+	}
+}
+
+// rowValue returns the value of the range without the surrounding spaces and its range.
+func (m *Document) rowValue(str string, r [2]int) (string, [2]int) {
+	for r[0] < r[1] && str[r[0]] == ' ' {
+		r[0]++
+	}
+	for r[1] > r[0] && str[r[1]-1] == ' ' {
+		r[1]--
+	}
+	value := str[r[0]:r[1]]
+	if !m.ColumnWidth && m.isCSV() {
+		value = csvUnquote(value)
+	}
+	return value, r
+}
+
+// columnNames returns the names of the columns from the header.
+func (m *Document) columnNames() []string {
+	if m.Header <= 0 {
+		return nil
+	}
+	str, err := m.LineStr(m.SkipLines)
+	if err != nil {
+		return nil
+	}
+	line := m.columnLine(str)
+	ranges, _ := m.columnRanges(m.SkipLines, line)
+	names := make([]string, 0, len(ranges))
+	for n := range ranges {
+		name, _ := m.columnValue(m.SkipLines, line, n)
+		names = append(names, name)
+	}
+	return names
+}
+
+// columnNumber returns the column number (from 0) of the name in the header,
+// or of the number (from 1).
+func (m *Document) columnNumber(name string) (int, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, false
+	}
+	names := m.columnNames()
+	if n := slices.Index(names, name); n >= 0 {
+		return n, true
+	}
+	for n, s := range names {
+		if strings.EqualFold(s, name) {
+			return n, true
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n > 0 {
+		return n - 1, true
+	}
+	return 0, false
+}
+
+// csvUnquote removes the quotes of the quoted field.
+func csvUnquote(str string) string {
+	if len(str) < 2 || str[0] != csvQuote || str[len(str)-1] != csvQuote {
+		return str
+	}
+	return strings.ReplaceAll(str[1:len(str)-1], `""`, `"`)
+}
+
+// columnRows calls fn for each row of the body with the first line number of the row,
+// the lines of the row and the value of the column.
+// found is false if the row does not have the column.
+// A CSV record with a line break in the quoted field is one row.
+// The lines are read in order without loading the chunks (see eachLine),
+// so it can be called outside the event loop.
+func (m *Document) columnRows(ctx context.Context, column int, fn func(lN int, lines [][]byte, value string, found bool) error) error {
+	csv := !m.ColumnWidth && m.isCSV()
+	delimiter, delimiterReg := m.ColumnDelimiter, m.ColumnDelimiterReg
+	start := -1
+	var lines [][]byte
+	var state csvState
+	flush := func() error {
+		if start < 0 {
+			return nil
+		}
+		value, found := m.rowColumnValue(start, lines, column, csv)
+		err := fn(start, lines, value, found)
+		start, lines = -1, nil
+		return err
+	}
+	err := m.eachLine(ctx, max(m.firstLine(), m.BufStartNum()), m.BufEndNum(), func(lN int, line []byte) error {
+		// The line following a line break in a quoted field is the same row.
+		if !state.quoted {
+			if err := flush(); err != nil {
+				return err
+			}
+			start = lN
+		}
+		lines = append(lines, line)
+		if csv {
+			state = csvNextState(string(line), state, delimiter, delimiterReg)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// rowColumnValue returns the value of the column n of the row starting at the line number.
+func (m *Document) rowColumnValue(lN int, lines [][]byte, n int, csv bool) (string, bool) {
+	if !csv {
+		return m.columnValue(lN, m.columnLine(string(lines[0])), n)
+	}
+	strs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		strs = append(strs, m.columnLine(string(line)).str)
+	}
+	value, _, found := m.columnValueOf(strings.Join(strs, "\n"), n)
+	return value, found
+}
+
+// columnName returns the name of the column n from the header.
+// It returns an empty string if there is no header.
+func (m *Document) columnName(n int) string {
+	names := m.columnNames()
+	if n < 0 || n >= len(names) {
+		return ""
+	}
+	return names[n]
+}
@@ -0,0 +1,193 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// exportTarget is the destination of the column export.
+type exportTarget int
+
+const (
+	// exportClipboard copies the column to the clipboard.
+	exportClipboard exportTarget = iota
+	// exportDocument opens the column as a new document.
+	exportDocument
+	// exportSave saves the column to a file.
+	exportSave
+)
+
+// exportTargetNames is the names of the export targets.
+var exportTargetNames = [...]string{
+	exportClipboard: "clipboard",
+	exportDocument:  "document",
+	exportSave:      "save",
+}
+
+// exportOption is the option of the column export.
+type exportOption struct {
+	target exportTarget
+	// marked exports only the marked lines.
+	marked bool
+}
+
+// parseExportOption parses the export option such as "document" and "save marked".
+// The empty string is the clipboard.
+func parseExportOption(str string) (exportOption, error) {
+	var opt exportOption
+	target := false
+	for _, f := range strings.Fields(str) {
+		if strings.EqualFold(f, "marked") {
+			opt.marked = true
+			continue
+		}
+		n := slices.IndexFunc(exportTargetNames[:], func(name string) bool {
+			return strings.EqualFold(f, name)
+		})
+		if n < 0 || target {
+			return opt, fmt.Errorf("%w: %s", ErrUnknownExportTarget, f)
+		}
+		opt.target = exportTarget(n)
+		target = true
+	}
+	return opt, nil
+}
+
+// columnExport is the values of the column of the rows.
+type columnExport struct {
+	// values is the values of the column.
+	values []string
+	// lineNumbers is the first line numbers of the rows.
+	lineNumbers []int
+}
+
+// add adds the value of the row.
+func (e *columnExport) add(lN int, value string) {
+	e.values = append(e.values, value)
+	e.lineNumbers = append(e.lineNumbers, lN)
+}
+
+// String returns the values one per line.
+func (e *columnExport) String() string {
+	var b strings.Builder
+	for _, v := range e.values {
+		b.WriteString(v)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// exportColumn returns the values of the column of the body.
+// If marks is not nil, only the rows that have the marked lines are exported.
+// The rows without the column have an empty value.
+func (m *Document) exportColumn(ctx context.Context, column int, marks []int) (*columnExport, error) {
+	var marked map[int]bool
+	if marks != nil {
+		marked = make(map[int]bool, len(marks))
+		for _, lN := range marks {
+			marked[lN] = true
+		}
+	}
+	export := &columnExport{}
+	err := m.columnRows(ctx, column, func(lN int, lines [][]byte, value string, _ bool) error {
+		if marked != nil && !rowMarked(marked, lN, len(lines)) {
+			return nil
+		}
+		export.add(lN, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// rowMarked returns true if one of the num lines of the row starting at the line number is marked.
+func rowMarked(marked map[int]bool, lN int, num int) bool {
+	for i := 0; i < num; i++ {
+		if marked[lN+i] {
+			return true
+		}
+	}
+	return false
+}
+
+// exportColumnTo extracts the column of the cursor in the background,
+// and sends it to the target of the input when it finishes.
+func (root *Root) exportColumnTo(ctx context.Context, input string) {
+	opt, err := parseExportOption(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	if opt.marked && len(m.marked) == 0 {
+		root.setMessage("No marked lines")
+		return
+	}
+	var marks []int
+	if opt.marked {
+		marks = slices.Clone(m.marked)
+	}
+	column := m.cursorColumn()
+	match := fmt.Sprintf("col%d", column+1)
+	root.setMessagef("export:%s (%v)Cancel", match, strings.Join(root.cancelKeys, ","))
+
+	root.runJob(ctx, "export", func(ctx context.Context) (func(context.Context), error) {
+		export, err := m.exportColumn(ctx, column, marks)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) {
+			root.sendColumnExport(ctx, m, opt, match, export)
+		}, nil
+	})
+}
+
+// sendColumnExport sends the extracted values to the target of the option.
+func (root *Root) sendColumnExport(ctx context.Context, m *Document, opt exportOption, match string, export *columnExport) {
+	if len(export.values) == 0 {
+		root.setMessagef("export:%s no rows", match)
+		return
+	}
+
+	switch opt.target {
+	case exportDocument:
+		root.exportColumnDocument(ctx, m, match, export)
+	case exportSave:
+		root.setSaveStringMode(export.String())
+	default:
+		root.copyClipboard(export.String(), fmt.Sprintf("Copy %s %d rows", match, len(export.values)))
+	}
+}
+
+// exportColumnDocument opens the values of the column as a new document.
+// The line numbers of the original document are stored in lineNumMap.
+func (root *Root) exportColumnDocument(ctx context.Context, m *Document, match string, export *columnExport) {
+	render, err := renderDoc(m, strings.NewReader(export.String()))
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	render.documentType = DocExport
+	render.Caption = fmt.Sprintf("export:%s", match)
+	renderLN := 0
+	for i, value := range export.values {
+		for n := strings.Count(value, "\n"); n >= 0; n-- {
+			render.lineNumMap.Store(renderLN, export.lineNumbers[i])
+			renderLN++
+		}
+	}
+	root.addDocument(ctx, render)
+	render.general = mergeGeneral(m.general, render.general)
+	render.ColumnMode = false
+	render.Header = 0
+	render.SkipLines = 0
+	root.setMessagef("export:%s", match)
+}
@@ -0,0 +1,193 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_parseExportOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    exportOption
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			str:  "",
+			want: exportOption{target: exportClipboard},
+		},
+		{
+			name: "document",
+			str:  "document",
+			want: exportOption{target: exportDocument},
+		},
+		{
+			name: "saveMarked",
+			str:  "Save marked",
+			want: exportOption{target: exportSave, marked: true},
+		},
+		{
+			name: "marked",
+			str:  "marked",
+			want: exportOption{target: exportClipboard, marked: true},
+		},
+		{
+			name:    "unknown",
+			str:     "printer",
+			wantErr: true,
+		},
+		{
+			name:    "twoTargets",
+			str:     "save document",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExportOption(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExportOption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseExportOption() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_exportColumn(t *testing.T) {
+	m := docHelper(t, "id,memo\n1,\"a\nb\"\n2,\n3,c\n")
+	m.setDelimiter(",")
+	m.Header = 1
+	ctx := context.Background()
+
+	got, err := m.exportColumn(ctx, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a\nb", "", "c"}; !reflect.DeepEqual(got.values, want) {
+		t.Errorf("Document.exportColumn() = %q, want %q", got.values, want)
+	}
+	if want := []int{1, 3, 4}; !reflect.DeepEqual(got.lineNumbers, want) {
+		t.Errorf("Document.exportColumn() lineNumbers = %v, want %v", got.lineNumbers, want)
+	}
+
+	// The marked lines in the same row are exported once, and the header is not exported.
+	got, err = m.exportColumn(ctx, 0, []int{4, 2, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "3"}; !reflect.DeepEqual(got.values, want) {
+		t.Errorf("Document.exportColumn() marked = %q, want %q", got.values, want)
+	}
+}
+
+func TestDocument_exportColumnLarge(t *testing.T) {
+	// The rows after the first chunk are read without loading the chunks.
+	var b strings.Builder
+	b.WriteString("id,v\n")
+	num := ChunkSize + 100
+	for i := 1; i < num; i++ {
+		fmt.Fprintf(&b, "%d,v%d\n", i, i)
+	}
+	fileName := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := docFileReadHelper(t, fileName)
+	m.setDelimiter(",")
+	m.Header = 1
+	ctx := context.Background()
+
+	got, err := m.exportColumn(ctx, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.values) != num-1 || got.values[num-2] != fmt.Sprintf("v%d", num-1) {
+		t.Errorf("Document.exportColumn() = %d values, want %d", len(got.values), num-1)
+	}
+	got, err = m.exportColumn(ctx, 1, []int{ChunkSize + 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{fmt.Sprintf("v%d", ChunkSize+50)}; !reflect.DeepEqual(got.values, want) {
+		t.Errorf("Document.exportColumn() marked = %q, want %q", got.values, want)
+	}
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Errorf("chunk 1 is loaded")
+	}
+}
+
+func TestDocument_exportColumnWidth(t *testing.T) {
+	m := docHelper(t, "AB12 xyz\nCD34 uvw\n")
+	m.ColumnWidth = true
+	m.ColumnPositions = []int{3, 5}
+	m.setColumnWidths(SCR{})
+	got, err := m.exportColumn(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "12\n34\n"; got.String() != want {
+		t.Errorf("Document.exportColumn() = %q, want %q", got.String(), want)
+	}
+}
+
+func TestRoot_exportColumnTo(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	parent := root.Doc
+	parent.Header = 1
+	parent.ColumnMode = true
+	parent.setDelimiter(",")
+	csvScanHelper(t, parent)
+	ctx := context.Background()
+
+	root.exportColumnTo(ctx, "document")
+	jobHelper(t, root)
+	if root.DocumentLen() != 2 {
+		t.Fatalf("exportColumnTo() documents = %d, want 2", root.DocumentLen())
+	}
+	m := root.Doc
+	eofHelper(t, m)
+	if m.documentType != DocExport {
+		t.Errorf("exportColumnTo() document type = %v, want %v", m.documentType, DocExport)
+	}
+	lines := make([]string, 0, m.BufEndNum())
+	for n := 0; n < m.BufEndNum(); n++ {
+		lines = append(lines, m.LineString(n))
+	}
+	if want := []string{"a", "b", "c, quoted", "d"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("exportColumnTo() = %q, want %q", lines, want)
+	}
+	if lN, ok := m.lineNumMap.LoadForward(2); !ok || lN != 3 {
+		t.Errorf("exportColumnTo() lineNumMap = %d, want 3", lN)
+	}
+
+	root.setDocument(ctx, parent)
+	root.exportColumnTo(ctx, "save")
+	jobHelper(t, root)
+	ev, ok := root.input.Event.(*eventSaveBuffer)
+	if !ok {
+		t.Fatalf("exportColumnTo() input = %T, want *eventSaveBuffer", root.input.Event)
+	}
+	fileName := filepath.Join(t.TempDir(), "export.txt")
+	root.saveString(fileName, ev.text)
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a\nb\nc, quoted\nd\n"; string(b) != want {
+		t.Errorf("saveString() = %q, want %q", string(b), want)
+	}
+}
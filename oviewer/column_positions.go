@@ -0,0 +1,145 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// positionWidths returns the column boundaries (see columnWidthRanges)
+// from the start positions of the columns (numbered from 1).
+// The first column always starts at 1.
+func positionWidths(positions []int) []int {
+	widths := make([]int, 0, len(positions))
+	for _, p := range positions {
+		if p > 1 {
+			widths = append(widths, p-2)
+		}
+	}
+	slices.Sort(widths)
+	return slices.Compact(widths)
+}
+
+// widthPositions returns the start positions of the columns (numbered from 1) from the column boundaries.
+func widthPositions(widths []int) []int {
+	positions := make([]int, 0, len(widths))
+	for _, w := range widths {
+		positions = append(positions, w+2)
+	}
+	return positions
+}
+
+// parseColumnPositions parses the positions separated by commas or spaces.
+func parseColumnPositions(str string) ([]int, error) {
+	fields := strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	positions := make([]int, 0, len(fields))
+	for _, f := range fields {
+		p, err := strconv.Atoi(f)
+		if err != nil || p < 1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNumber, f)
+		}
+		positions = append(positions, p)
+	}
+	return positions, nil
+}
+
+// setColumnPositions sets the start positions of the columns and turns on the ColumnWidth mode.
+// An empty input clears the positions, and the columns are guessed again.
+func (root *Root) setColumnPositions(input string) {
+	m := root.Doc
+	positions, err := parseColumnPositions(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m.ColumnPositions = widthPositions(positionWidths(positions))
+	m.ColumnWidth = true
+	m.ColumnMode = true
+	m.columnWidths = nil
+	m.resetAlign()
+	root.setMessagef("Set ColumnPositions %v", m.ColumnPositions)
+}
+
+// moveColumnBoundaryLeft moves the right boundary of the column of the cursor to the left.
+func (root *Root) moveColumnBoundaryLeft(context.Context) {
+	root.moveColumnBoundary(-root.repeatCount())
+}
+
+// moveColumnBoundaryRight moves the right boundary of the column of the cursor to the right.
+func (root *Root) moveColumnBoundaryRight(context.Context) {
+	root.moveColumnBoundary(root.repeatCount())
+}
+
+// moveColumnBoundary moves the right boundary of the column of the cursor by n.
+// The guessed boundaries become the ColumnPositions.
+func (root *Root) moveColumnBoundary(n int) {
+	m := root.Doc
+	if !m.ColumnMode || !m.ColumnWidth {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	c := m.cursorColumn()
+	if c < 0 || c >= len(m.columnWidths) {
+		root.setMessage(ErrNoColumn.Error())
+		return
+	}
+	positions := widthPositions(m.columnWidths)
+	low, high := 2, max(m.rightmost(root.scr)+1, positions[c])
+	if c > 0 {
+		low = positions[c-1] + 1
+	}
+	if c < len(positions)-1 {
+		high = positions[c+1] - 1
+	}
+	positions[c] = max(low, min(positions[c]+n, high))
+	m.ColumnPositions = positions
+	m.columnWidths = positionWidths(positions)
+	m.resetAlign()
+	root.setMessagef("Set ColumnPositions %v", m.ColumnPositions)
+}
+
+// rulerContents returns the ruler from the position left (from 0) for the width.
+// The positions of the columns are marked with "|".
+func rulerContents(left int, width int, positions []int, markStyle OVStyle) contents {
+	lc := make(contents, 0, width)
+	for x := left; x < left+width; x++ {
+		p := x + 1
+		c := content{mainc: '-', width: 1, style: defaultStyle}
+		switch {
+		case slices.Contains(positions, p):
+			c.mainc = '|'
+			c.style = applyStyle(defaultStyle, markStyle)
+		case p%10 == 0:
+			c.mainc = rune('0' + (p/10)%10)
+		case p%5 == 0:
+			c.mainc = '+'
+		}
+		lc = append(lc, c)
+	}
+	return lc
+}
+
+// drawRuler draws the ruler above the status line while the column positions are input.
+// The positions being input are marked.
+func (root *Root) drawRuler() {
+	m := root.Doc
+	y := m.statusPos - 1
+	if y < 0 {
+		return
+	}
+	positions, err := parseColumnPositions(root.input.value)
+	if err != nil {
+		positions = widthPositions(m.columnWidths)
+	}
+	left := 0
+	if !m.WrapMode {
+		left = max(0, m.x)
+	}
+	root.clearY(y)
+	width := root.scr.vWidth - root.scr.startX
+	root.setContentString(root.scr.startX, y, rulerContents(left, width, positions, root.StyleColumnHighlight))
+}
@@ -0,0 +1,120 @@
+package oviewer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_positionWidths(t *testing.T) {
+	widths := positionWidths([]int{9, 1, 3, 9})
+	if want := []int{1, 7}; !reflect.DeepEqual(widths, want) {
+		t.Errorf("positionWidths() = %v, want %v", widths, want)
+	}
+	if got, want := widthPositions(widths), []int{3, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("widthPositions() = %v, want %v", got, want)
+	}
+}
+
+func Test_parseColumnPositions(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    []int
+		wantErr bool
+	}{
+		{str: "9,18", want: []int{9, 18}},
+		{str: "9 18, 30", want: []int{9, 18, 30}},
+		{str: "", want: []int{}},
+		{str: "9,a", wantErr: true},
+		{str: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseColumnPositions(tt.str)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseColumnPositions(%q) error = %v, wantErr %v", tt.str, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseColumnPositions(%q) = %v, want %v", tt.str, got, tt.want)
+		}
+	}
+}
+
+func TestDocument_columnWidthRangesPositions(t *testing.T) {
+	m := docHelper(t, "AB12 xyz\n")
+	m.ColumnWidth = true
+	m.ColumnPositions = []int{3, 5}
+	m.setColumnWidths(SCR{})
+	line := m.columnLine("AB12 xyz")
+	// The columns are adjacent.
+	if got, want := m.columnWidthRanges(line.lc), [][2]int{{0, 2}, {2, 4}, {4, 8}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.columnWidthRanges() = %v, want %v", got, want)
+	}
+	for n, want := range []string{"AB", "12", "xyz"} {
+		if got, _, _ := m.columnValueOf("AB12 xyz", n); got != want {
+			t.Errorf("Document.columnValueOf(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestRoot_moveColumnBoundary(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "ps.txt"))
+	m := root.Doc
+	m.ColumnMode = true
+	m.ColumnWidth = true
+	m.columnWidths = []int{3, 9, 15}
+	m.columnCursor = 1
+	root.moveColumnBoundary(2)
+	if want := []int{5, 13, 17}; !reflect.DeepEqual(m.ColumnPositions, want) {
+		t.Errorf("moveColumnBoundary() = %v, want %v", m.ColumnPositions, want)
+	}
+	// The boundary does not pass the next boundary.
+	root.moveColumnBoundary(10)
+	if want := []int{5, 16, 17}; !reflect.DeepEqual(m.ColumnPositions, want) {
+		t.Errorf("moveColumnBoundary() = %v, want %v", m.ColumnPositions, want)
+	}
+	root.moveColumnBoundary(-20)
+	if want := []int{5, 6, 17}; !reflect.DeepEqual(m.ColumnPositions, want) {
+		t.Errorf("moveColumnBoundary() = %v, want %v", m.ColumnPositions, want)
+	}
+	if want := []int{3, 4, 15}; !reflect.DeepEqual(m.columnWidths, want) {
+		t.Errorf("moveColumnBoundary() columnWidths = %v, want %v", m.columnWidths, want)
+	}
+}
+
+func TestRoot_setColumnPositions(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "ps.txt"))
+	root.setColumnPositions("18,1,9")
+	m := root.Doc
+	if !m.ColumnMode || !m.ColumnWidth {
+		t.Errorf("setColumnPositions() ColumnMode = %v, ColumnWidth = %v", m.ColumnMode, m.ColumnWidth)
+	}
+	if want := []int{9, 18}; !reflect.DeepEqual(m.ColumnPositions, want) {
+		t.Errorf("setColumnPositions() = %v, want %v", m.ColumnPositions, want)
+	}
+	root.setColumnPositions("x")
+	if want := []int{9, 18}; !reflect.DeepEqual(m.ColumnPositions, want) {
+		t.Errorf("setColumnPositions() invalid = %v, want %v", m.ColumnPositions, want)
+	}
+}
+
+func Test_rulerContents(t *testing.T) {
+	lc := rulerContents(0, 12, []int{5}, OVStyle{})
+	if got, _ := ContentsToStr(lc); got != "----|----1--" {
+		t.Errorf("rulerContents() = %q", got)
+	}
+	lc = rulerContents(95, 10, nil, OVStyle{})
+	if got, _ := ContentsToStr(lc); got != "----0----+" {
+		t.Errorf("rulerContents() = %q", got)
+	}
+}
@@ -0,0 +1,200 @@
+package oviewer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// columnOperators is the operators of the column condition.
+// The longer operators come first.
+var columnOperators = []string{">=", "<=", "!=", "==", "!~", ">", "<", "=", "~"}
+
+// columnCondition is the condition of the column search.
+type columnCondition struct {
+	// searcher matches the value when there is no operator.
+	searcher Searcher
+	// re is the regular expression of ~ and !~.
+	re *regexp.Regexp
+	// op is the operator.
+	op string
+	// value is the value to compare.
+	value string
+	// column is the column to search (numbered from 0).
+	column int
+}
+
+// parseColumnCondition parses the column condition like "status>=500" or "host~^web".
+// The column is the name in the header or the number (from 1).
+// If the input is not a condition, it searches the word in the column of the cursor.
+func (m *Document) parseColumnCondition(str string, cursor int) (columnCondition, error) {
+	i := strings.IndexAny(str, "<>=!~")
+	if i <= 0 {
+		return columnCondition{column: cursor}, nil
+	}
+	column, ok := m.columnNumber(str[:i])
+	if !ok {
+		return columnCondition{column: cursor}, nil
+	}
+	op := ""
+	for _, o := range columnOperators {
+		if strings.HasPrefix(str[i:], o) {
+			op = o
+			break
+		}
+	}
+	if op == "" {
+		return columnCondition{column: cursor}, nil
+	}
+	cond := columnCondition{
+		column: column,
+		op:     op,
+		value:  strings.TrimSpace(str[i+len(op):]),
+	}
+	if op == "~" || op == "!~" {
+		re, err := regexp.Compile(cond.value)
+		if err != nil {
+			return cond, fmt.Errorf("%w: %s", ErrInvalidRegexp, cond.value)
+		}
+		cond.re = re
+	}
+	return cond, nil
+}
+
+// match returns true if the value satisfies the condition.
+// If the value of the condition is numeric, only the numeric values satisfy it.
+func (cond columnCondition) match(value string) bool {
+	switch cond.op {
+	case "":
+		return cond.searcher.MatchString(value)
+	case "~":
+		return cond.re.MatchString(value)
+	case "!~":
+		return !cond.re.MatchString(value)
+	}
+
+	c := strings.Compare(value, cond.value)
+	// A numeric value is compared numerically,
+	// and the non-numeric value (such as "-" and "n/a") does not match it.
+	if b, ok := parseSortNumber(cond.value); ok {
+		a, ok := parseSortNumber(value)
+		if !ok {
+			return false
+		}
+		c = cmp.Compare(a, b)
+	}
+	switch cond.op {
+	case "=", "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// columnSearcher is a search restricted to a column.
+type columnSearcher struct {
+	// value returns the value of the column and its byte range.
+	value func(str string, n int) (string, [2]int, bool)
+	word  string
+	cond  columnCondition
+}
+
+// columnSearcher Match is a column search for bytes.
+func (s *columnSearcher) Match(target []byte) bool {
+	return s.MatchString(string(stripEscapeSequenceBytes(target)))
+}
+
+// columnSearcher MatchString is a column search for string.
+func (s *columnSearcher) MatchString(target string) bool {
+	target = stripEscapeSequenceString(target)
+	value, _, ok := s.value(target, s.cond.column)
+	if !ok {
+		return false
+	}
+	return s.cond.match(value)
+}
+
+// columnSearcher FindAll returns the matches in the column,
+// or the whole column if the column satisfies the condition.
+func (s *columnSearcher) FindAll(target string) [][]int {
+	value, r, ok := s.value(target, s.cond.column)
+	if !ok || !s.cond.match(value) {
+		return nil
+	}
+	if s.cond.op != "" {
+		return [][]int{{r[0], r[1]}}
+	}
+	indexes := s.cond.searcher.FindAll(target[r[0]:r[1]])
+	for _, idx := range indexes {
+		idx[0] += r[0]
+		idx[1] += r[0]
+	}
+	return indexes
+}
+
+// columnSearcher String returns the search word.
+func (s *columnSearcher) String() string {
+	return s.word
+}
+
+// setColumnSearcher returns the column searcher of the input.
+// Returns nil if there is no search term.
+func (root *Root) setColumnSearcher(str string) (Searcher, error) {
+	if str == "" {
+		root.searcher = nil
+		return nil, nil
+	}
+	m := root.Doc
+	if !m.ColumnMode {
+		return nil, ErrNotColumnMode
+	}
+	cond, err := m.parseColumnCondition(str, m.cursorColumn())
+	if err != nil {
+		return nil, err
+	}
+	if cond.op == "" {
+		cond.searcher = root.newSearcher(str, root.Config.CaseSensitive)
+	}
+	searcher := &columnSearcher{
+		value: m.columnValueOf,
+		word:  str,
+		cond:  cond,
+	}
+	root.searcher = searcher
+	return searcher, nil
+}
+
+// columnSearch searches forward in the column.
+func (root *Root) columnSearch(ctx context.Context, str string) {
+	searcher, err := root.setColumnSearcher(str)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.searchMove(ctx, true, root.startSearchLN(), searcher)
+}
+
+// columnFilter filters the document by the column.
+func (root *Root) columnFilter(ctx context.Context, str string) {
+	searcher, err := root.setColumnSearcher(str)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	if searcher == nil {
+		return
+	}
+	root.Doc.nonMatch = false
+	root.filterDocument(ctx, searcher)
+}
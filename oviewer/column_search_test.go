@@ -0,0 +1,191 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestDocument_parseColumnCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		str        string
+		wantColumn int
+		wantOp     string
+		wantValue  string
+		wantErr    bool
+	}{
+		{
+			name:       "name",
+			str:        "status>=500",
+			wantColumn: 1,
+			wantOp:     ">=",
+			wantValue:  "500",
+		},
+		{
+			name:       "regexp",
+			str:        "host~^web",
+			wantColumn: 0,
+			wantOp:     "~",
+			wantValue:  "^web",
+		},
+		{
+			name:       "caseInsensitiveName",
+			str:        "Status != 200",
+			wantColumn: 1,
+			wantOp:     "!=",
+			wantValue:  "200",
+		},
+		{
+			name:       "number",
+			str:        "3=GET",
+			wantColumn: 2,
+			wantOp:     "=",
+			wantValue:  "GET",
+		},
+		{
+			name:       "word",
+			str:        "200",
+			wantColumn: 4,
+		},
+		{
+			name:       "unknownColumn",
+			str:        "x=1",
+			wantColumn: 4,
+		},
+		{
+			name:    "invalidRegexp",
+			str:     "host~(",
+			wantOp:  "~",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, "host,status,method\nweb1,200,GET\n")
+			m.setDelimiter(",")
+			m.Header = 1
+			got, err := m.parseColumnCondition(tt.str, 4)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.parseColumnCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.column != tt.wantColumn || got.op != tt.wantOp || got.value != tt.wantValue {
+				t.Errorf("Document.parseColumnCondition() = %d %q %q, want %d %q %q", got.column, got.op, got.value, tt.wantColumn, tt.wantOp, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_columnCondition_match(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		value string
+		str   string
+		want  bool
+	}{
+		{name: "numberGreater", op: ">=", value: "500", str: "503", want: true},
+		{name: "numberNotLexical", op: ">", value: "9", str: "10", want: true},
+		{name: "numberLess", op: "<", value: "500", str: "1,000", want: false},
+		{name: "equalNumber", op: "=", value: "200", str: "200.0", want: true},
+		{name: "notNumberLess", op: "<", value: "500", str: "-", want: false},
+		{name: "notNumberGreater", op: ">", value: "500", str: "n/a", want: false},
+		{name: "notNumberNotEqual", op: "!=", value: "0", str: "n/a", want: false},
+		{name: "lexical", op: "<", value: "b", str: "a", want: true},
+		{name: "notEqual", op: "!=", value: "GET", str: "POST", want: true},
+		{name: "regexp", op: "~", value: "^web", str: "web1", want: true},
+		{name: "notRegexp", op: "!~", value: "^web", str: "web1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, "a\n")
+			cond, err := m.parseColumnCondition("1"+tt.op+tt.value, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cond.match(tt.str); got != tt.want {
+				t.Errorf("columnCondition.match(%q) = %v, want %v", tt.str, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_columnSearcher(t *testing.T) {
+	m := docHelper(t, "host,status,bytes\n")
+	m.setDelimiter(",")
+	m.Header = 1
+	root := &Root{Doc: m}
+	m.ColumnMode = true
+	m.columnCursor = 1
+	searcher, err := root.setColumnSearcher("200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if searcher.MatchString("200,404,1200") {
+		t.Errorf("columnSearcher.MatchString() matches the other column")
+	}
+	if !searcher.Match([]byte("web,200,1200")) {
+		t.Errorf("columnSearcher.Match() does not match the column")
+	}
+	if got, want := searcher.FindAll("web,200,1200"), [][]int{{4, 7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columnSearcher.FindAll() = %v, want %v", got, want)
+	}
+
+	searcher, err = root.setColumnSearcher("bytes>1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := searcher.FindAll("web, 200 , 1200 "), [][]int{{11, 15}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columnSearcher.FindAll() = %v, want %v", got, want)
+	}
+	if searcher.MatchString("web,200,999") {
+		t.Errorf("columnSearcher.MatchString() matches 999")
+	}
+}
+
+func TestDocument_columnValueOfWidth(t *testing.T) {
+	m := docHelper(t, "a\n")
+	m.ColumnWidth = true
+	m.columnWidths = []int{4, 9}
+	value, r, ok := m.columnValueOf("web  200   1200", 1)
+	if !ok || value != "200" || r != [2]int{5, 8} {
+		t.Errorf("Document.columnValueOf() = %q %v %v, want \"200\" [5 8] true", value, r, ok)
+	}
+}
+
+func TestRoot_columnFilter(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	root.Doc.Header = 1
+	root.Doc.ColumnMode = true
+	root.Doc.setDelimiter(",")
+	root.columnFilter(context.Background(), "latency>=30")
+	if root.DocumentLen() != 2 {
+		t.Fatalf("columnFilter() documents = %d, want 2", root.DocumentLen())
+	}
+	filtered := root.Doc
+	for !filtered.BufEOF() {
+	}
+	want := []string{
+		"name,size,latency,time",
+		"a,1K,30,2024-01-02T00:00:00Z",
+		`"c, quoted",2M,100,2023-12-31T00:00:00Z`,
+	}
+	if filtered.BufEndNum() != len(want) {
+		t.Fatalf("columnFilter() lines = %d, want %d", filtered.BufEndNum(), len(want))
+	}
+	for i, w := range want {
+		if got := filtered.LineString(i); got != w {
+			t.Errorf("columnFilter() line %d = %q, want %q", i, got, w)
+		}
+	}
+}
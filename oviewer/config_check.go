@@ -0,0 +1,239 @@
+package oviewer
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ConfigError is a problem found in the config.
+type ConfigError struct {
+	// Path is the path of the key (e.g. ["Keybind", "exit"]).
+	Path []string
+	// Err is the problem.
+	Err error
+}
+
+// Error returns the path and the problem.
+func (e ConfigError) Error() string {
+	return strings.Join(e.Path, ".") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the problem.
+func (e ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// CheckConfig returns the problems of the config.
+// It checks the styles, the regular expressions, the modes, the key bindings and the macros.
+func CheckConfig(config Config) []ConfigError {
+	var errs []ConfigError
+	errs = append(errs, checkStyles(config)...)
+	errs = append(errs, checkGeneral([]string{"General"}, config.General)...)
+	for name, mode := range config.Mode {
+		errs = append(errs, checkGeneral([]string{"Mode", name}, mode)...)
+	}
+	if config.ViewMode != "" {
+		if _, ok := config.Mode[config.ViewMode]; !ok {
+			errs = append(errs, ConfigError{Path: []string{"ViewMode"}, Err: fmt.Errorf("%w: %s", ErrUnknownMode, config.ViewMode)})
+		}
+	}
+	for i, rule := range config.ModeRule {
+		path := []string{"ModeRule", strconv.Itoa(i)}
+		if _, ok := config.Mode[rule.Mode]; !ok && rule.Mode != "general" {
+			errs = append(errs, ConfigError{Path: append(path, "Mode"), Err: fmt.Errorf("%w: %s", ErrUnknownMode, rule.Mode)})
+		}
+		if rule.Content != "" {
+			if _, err := regexp.Compile(rule.Content); err != nil {
+				errs = append(errs, ConfigError{Path: append(path, "Content"), Err: fmt.Errorf("%w: %w", ErrInvalidRegexp, err)})
+			}
+		}
+	}
+	errs = append(errs, checkKeyBind(config)...)
+	errs = append(errs, checkMacro(config)...)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return strings.Join(errs[i].Path, ".") < strings.Join(errs[j].Path, ".")
+	})
+	return errs
+}
+
+// checkStyles checks the colors of the styles.
+func checkStyles(config Config) []ConfigError {
+	var errs []ConfigError
+	v := reflect.ValueOf(config)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		switch s := v.Field(i).Interface().(type) {
+		case OVStyle:
+			errs = append(errs, checkStyle([]string{name}, s)...)
+		case []OVStyle:
+			for n, style := range s {
+				errs = append(errs, checkStyle([]string{name, strconv.Itoa(n)}, style)...)
+			}
+		}
+	}
+	return errs
+}
+
+// checkStyle checks the colors of the style.
+func checkStyle(path []string, s OVStyle) []ConfigError {
+	var errs []ConfigError
+	if !validColor(s.Background) {
+		errs = append(errs, ConfigError{Path: append(path, "Background"), Err: fmt.Errorf("%w: %s", ErrInvalidColor, s.Background)})
+	}
+	if !validColor(s.Foreground) {
+		errs = append(errs, ConfigError{Path: append(path, "Foreground"), Err: fmt.Errorf("%w: %s", ErrInvalidColor, s.Foreground)})
+	}
+	return errs
+}
+
+// validColor returns true if the color name can be used.
+func validColor(name string) bool {
+	if name == "" || strings.EqualFold(name, "default") {
+		return true
+	}
+	return tcell.GetColor(name) != tcell.ColorDefault
+}
+
+// checkGeneral checks the regular expressions of the general settings.
+func checkGeneral(path []string, g general) []ConfigError {
+	var errs []ConfigError
+	if d := g.ColumnDelimiter; len(d) > 2 && d[0] == '/' && d[len(d)-1] == '/' {
+		if _, err := regexp.Compile(d[1 : len(d)-1]); err != nil {
+			errs = append(errs, ConfigError{Path: append(path, "ColumnDelimiter"), Err: fmt.Errorf("%w: %w", ErrInvalidRegexp, err)})
+		}
+	}
+	// The invalid regular expression is used as a string, so it is reported.
+	if g.SectionDelimiter != "" {
+		if _, err := regexp.Compile(g.SectionDelimiter); err != nil {
+			errs = append(errs, ConfigError{Path: append(path, "SectionDelimiter"), Err: fmt.Errorf("%w: %w", ErrInvalidRegexp, err)})
+		}
+	}
+	for i, w := range g.MultiColorWords {
+		s, err := strconv.Unquote(w)
+		if err != nil {
+			s = w
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			errs = append(errs, ConfigError{Path: append(path, "MultiColorWords", strconv.Itoa(i)), Err: fmt.Errorf("%w: %w", ErrInvalidRegexp, err)})
+		}
+	}
+	return errs
+}
+
+// checkKeyBind checks the actions and keys of the key bindings,
+// and the keys assigned to multiple actions.
+func checkKeyBind(config Config) []ConfigError {
+	var errs []ConfigError
+	var root Root
+	actionHandlers := root.handlers()
+	for name, keys := range config.Keybind {
+		path := []string{"Keybind", name}
+		if _, ok := actionHandlers[name]; !ok {
+			errs = append(errs, ConfigError{Path: path, Err: fmt.Errorf("%w: %s", ErrUnknownAction, name)})
+			continue
+		}
+		for _, k := range keys {
+			for _, s := range strings.Fields(k) {
+				if _, err := decodeKeyStroke(s); err != nil {
+					errs = append(errs, ConfigError{Path: path, Err: fmt.Errorf("%w [%s]: %w", ErrFailedKeyBind, k, err)})
+					break
+				}
+			}
+		}
+	}
+
+	// Keys assigned to multiple actions, including the default key bindings.
+	keyBind := GetKeyBinds(config)
+	names := make([]string, 0, len(keyBind))
+	for name := range keyBind {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assigned := make(map[string]string)
+	var seqs, singles []keySequence
+	for _, name := range names {
+		if _, ok := actionHandlers[name]; !ok {
+			continue
+		}
+		for _, k := range keyBind[name] {
+			key, ok := normalizeKey(k)
+			if !ok {
+				continue
+			}
+			if isInputAction(name) {
+				key = "input:" + key
+			}
+			other, ok := assigned[key]
+			if !ok {
+				assigned[key] = name
+				if seq, err := newKeySequence(name, k, nil); err == nil {
+					if isKeySequence(k) {
+						seqs = append(seqs, seq)
+					} else if !isInputAction(name) {
+						singles = append(singles, seq)
+					}
+				}
+				continue
+			}
+			// Report the action in the config file.
+			path := []string{"Keybind", name}
+			if _, ok := config.Keybind[name]; !ok {
+				path = []string{"Keybind", other}
+			}
+			errs = append(errs, ConfigError{Path: path, Err: fmt.Errorf("%w [%s] for %s and %s", ErrKeyBindConflict, k, other, name)})
+		}
+	}
+	if err := keySequenceConflict(seqs, singles); err != nil {
+		errs = append(errs, ConfigError{Path: []string{"Keybind"}, Err: err})
+	}
+	return errs
+}
+
+// normalizeKey returns the string that represents the same key.
+func normalizeKey(k string) (string, bool) {
+	fields := strings.Fields(k)
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		s, err := decodeKeyStroke(f)
+		if err != nil {
+			return "", false
+		}
+		keys = append(keys, fmt.Sprintf("%d:%d:%d", s.mod, s.key, s.ch))
+	}
+	return strings.Join(keys, " "), true
+}
+
+// checkMacro checks the actions of the macros and the macros of MacroKeybind.
+func checkMacro(config Config) []ConfigError {
+	var errs []ConfigError
+	var root Root
+	actionHandlers := root.handlers()
+	for name, steps := range config.Macro {
+		for i, step := range steps {
+			_, ok := actionHandlers[step.Action]
+			if !ok || isInputAction(step.Action) || isMacroAction(step.Action) {
+				errs = append(errs, ConfigError{Path: []string{"Macro", name, strconv.Itoa(i), "Action"}, Err: fmt.Errorf("%w: %s", ErrUnknownAction, step.Action)})
+			}
+		}
+	}
+	for name, keys := range config.MacroKeybind {
+		path := []string{"MacroKeybind", name}
+		if _, ok := config.Macro[name]; !ok {
+			errs = append(errs, ConfigError{Path: path, Err: fmt.Errorf("%w: %s", ErrMacroNotFound, name)})
+		}
+		for _, k := range keys {
+			if _, ok := normalizeKey(k); !ok {
+				errs = append(errs, ConfigError{Path: path, Err: fmt.Errorf("%w [%s]", ErrFailedKeyBind, k)})
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,155 @@
+package oviewer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   func() Config
+		wantPath string
+		wantErr  error
+	}{
+		{
+			name:   "default",
+			config: NewConfig,
+		},
+		{
+			name: "invalid color",
+			config: func() Config {
+				c := NewConfig()
+				c.StyleColumnRainbow[1].Foreground = "notacolor"
+				return c
+			},
+			wantPath: "StyleColumnRainbow.1.Foreground",
+			wantErr:  ErrInvalidColor,
+		},
+		{
+			name: "invalid section delimiter",
+			config: func() Config {
+				c := NewConfig()
+				c.Mode = map[string]general{"markdown": {SectionDelimiter: "^(#"}}
+				return c
+			},
+			wantPath: "Mode.markdown.SectionDelimiter",
+			wantErr:  ErrInvalidRegexp,
+		},
+		{
+			name: "invalid column delimiter",
+			config: func() Config {
+				c := NewConfig()
+				c.General.ColumnDelimiter = "/[/"
+				return c
+			},
+			wantPath: "General.ColumnDelimiter",
+			wantErr:  ErrInvalidRegexp,
+		},
+		{
+			name: "unknown view mode",
+			config: func() Config {
+				c := NewConfig()
+				c.ViewMode = "markdown"
+				return c
+			},
+			wantPath: "ViewMode",
+			wantErr:  ErrUnknownMode,
+		},
+		{
+			name: "unknown action",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"exitx": {"q"}}
+				return c
+			},
+			wantPath: "Keybind.exitx",
+			wantErr:  ErrUnknownAction,
+		},
+		{
+			name: "invalid key",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"exit": {"ctrl+zz"}}
+				return c
+			},
+			wantPath: "Keybind.exit",
+			wantErr:  ErrFailedKeyBind,
+		},
+		{
+			name: "key conflict",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"exit": {"Escape", "n"}}
+				return c
+			},
+			wantPath: "Keybind.exit",
+			wantErr:  ErrKeyBindConflict,
+		},
+		{
+			name: "input key no conflict",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"input_previous": {"n"}}
+				return c
+			},
+		},
+		{
+			name: "key sequence conflict",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"exit": {"Escape", "z z"}, "top": {"z z x"}}
+				return c
+			},
+			wantPath: "Keybind",
+			wantErr:  ErrKeySequenceConflict,
+		},
+		{
+			name: "key sequence and single key conflict",
+			config: func() Config {
+				c := NewConfig()
+				c.Keybind = map[string][]string{"top": {"g g"}}
+				return c
+			},
+			wantPath: "Keybind",
+			wantErr:  ErrKeySequenceConflict,
+		},
+		{
+			name: "unknown macro action",
+			config: func() Config {
+				c := NewConfig()
+				c.Macro = map[string][]MacroStep{"m": {{Action: "macro_play"}}}
+				return c
+			},
+			wantPath: "Macro.m.0.Action",
+			wantErr:  ErrUnknownAction,
+		},
+		{
+			name: "unknown macro key",
+			config: func() Config {
+				c := NewConfig()
+				c.MacroKeybind = map[string][]string{"m": {"F5"}}
+				return c
+			},
+			wantPath: "MacroKeybind.m",
+			wantErr:  ErrMacroNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := CheckConfig(tt.config())
+			if tt.wantErr == nil {
+				if len(errs) != 0 {
+					t.Errorf("CheckConfig() = %v, want no errors", errs)
+				}
+				return
+			}
+			for _, e := range errs {
+				if e.Error()[:len(tt.wantPath)] == tt.wantPath && errors.Is(e, tt.wantErr) {
+					return
+				}
+			}
+			t.Errorf("CheckConfig() = %v, want %s %v", errs, tt.wantPath, tt.wantErr)
+		})
+	}
+}
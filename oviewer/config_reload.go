@@ -0,0 +1,252 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"time"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"github.com/fsnotify/fsnotify"
+	"github.com/gdamore/tcell/v2"
+)
+
+// configReloadDelay is the time to wait for the writes of the config file to finish.
+const configReloadDelay = 200 * time.Millisecond
+
+// WatchConfig reloads the config when one of the config files is changed.
+// load reads the config files again and returns the new config and the config files read,
+// because the included files and the per-directory config files may change.
+// The config files are watched by the watcher of Run.
+func (root *Root) WatchConfig(fileNames []string, load func() (Config, []string, error)) {
+	root.configFiles = absConfigFiles(fileNames)
+	root.configLoader = load
+}
+
+// absConfigFiles returns the absolute paths of the config files.
+func absConfigFiles(fileNames []string) []string {
+	files := make([]string, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		path, err := filepath.Abs(fileName)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+// watchConfig adds the directories of the config files to the watcher.
+// The directory is watched because editors often replace the file.
+func (root *Root) watchConfig(watcher *fsnotify.Watcher) {
+	if root.configLoader == nil {
+		return
+	}
+	root.configWatcher = watcher
+	root.addConfigWatch()
+}
+
+// addConfigWatch adds the directories of the config files to the watcher.
+// Adding the directory already watched does nothing.
+// The directories no longer needed are kept,
+// because the watcher is shared with the documents and the other events are ignored.
+func (root *Root) addConfigWatch() {
+	if root.configWatcher == nil {
+		return
+	}
+	for _, fileName := range root.configFiles {
+		if err := root.configWatcher.Add(filepath.Dir(fileName)); err != nil {
+			log.Printf("watch config %s: %s", fileName, err)
+		}
+	}
+}
+
+// updateConfigFiles replaces the config files to watch with the files read by the reload.
+func (root *Root) updateConfigFiles(fileNames []string) {
+	files := absConfigFiles(fileNames)
+	root.mu.Lock()
+	root.configFiles = files
+	root.mu.Unlock()
+	root.addConfigWatch()
+}
+
+// watchConfigEvent fires the reload event if the config file is changed.
+// It returns true if the event is for the config file.
+func (root *Root) watchConfigEvent(event fsnotify.Event) bool {
+	if root.configLoader == nil || !slices.Contains(root.configFiles, event.Name) {
+		return false
+	}
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+		return true
+	}
+	// Editors write the file several times, so reload after the last write.
+	if root.configTimer != nil {
+		root.configTimer.Stop()
+	}
+	root.configTimer = time.AfterFunc(configReloadDelay, func() {
+		ev := &eventReloadConfig{}
+		ev.SetEventNow()
+		root.postEvent(ev)
+	})
+	return true
+}
+
+// eventReloadConfig represents a change of the config file.
+type eventReloadConfig struct {
+	tcell.EventTime
+}
+
+// reloadConfig reads the config files again and applies the config.
+// The key bindings, the styles and the modes are applied, and the positions of the documents are kept.
+// The config files to watch are updated to the files read.
+// If the config cannot be applied, the current config is kept.
+func (root *Root) reloadConfig(ctx context.Context) {
+	config, fileNames, err := root.configLoader()
+	if err != nil {
+		root.setMessageLogf("config reload: %s", err)
+		return
+	}
+	root.updateConfigFiles(fileNames)
+	if err := root.applyConfig(ctx, config); err != nil {
+		root.setMessageLogf("config reload: %s", err)
+		return
+	}
+	root.setMessageLog("config reloaded")
+}
+
+// applyConfig sets the config and rebuilds the key bindings.
+// The settings changed in the config are applied to the normal documents.
+// If the config cannot be applied, nothing is changed.
+func (root *Root) applyConfig(ctx context.Context, config Config) error {
+	oldConfig := root.Config
+	oldKeyConfig, oldInputKeyConfig := root.keyConfig, root.inputKeyConfig
+	oldKeySequences := root.keySequences
+	oldKeyBinds, oldCancelKeys := root.keyBinds, root.cancelKeys
+	restore := func() {
+		root.Config = oldConfig
+		root.keyConfig, root.inputKeyConfig = oldKeyConfig, oldInputKeyConfig
+		root.keySequences = oldKeySequences
+		root.keyBinds, root.cancelKeys = oldKeyBinds, oldCancelKeys
+	}
+
+	root.SetConfig(config)
+	root.keyConfig = cbind.NewConfiguration()
+	root.inputKeyConfig = cbind.NewConfiguration()
+	keyBind, err := root.setKeyConfig(ctx)
+	if err != nil {
+		restore()
+		return err
+	}
+	help, err := NewHelp(keyBind)
+	if err != nil {
+		restore()
+		return fmt.Errorf("help: %w", err)
+	}
+	if root.Doc == root.helpDoc {
+		root.Doc = help
+	}
+	root.helpDoc = help
+
+	OverStrikeStyle = ToTcellStyle(root.Config.StyleOverStrike)
+	OverLineStyle = ToTcellStyle(root.Config.StyleOverLine)
+	Preprocessors = root.Config.Preprocessor
+	if root.Config.DisableMouse {
+		root.Screen.DisableMouse()
+	} else {
+		root.Screen.EnableMouse(MouseFlags)
+	}
+
+	root.setModeConfig()
+	root.mu.RLock()
+	for _, m := range root.DocList {
+		if m.documentType == DocNormal {
+			root.reapplyGeneral(m, oldConfig)
+		}
+		// The styles may have changed.
+		m.ClearCache()
+	}
+	root.mu.RUnlock()
+	root.ViewSync(ctx)
+	return nil
+}
+
+// reapplyGeneral applies the general settings and the view mode that changed from oldConfig to the document.
+// The settings changed interactively are kept, and the position of the document is not changed.
+func (root *Root) reapplyGeneral(m *Document, oldConfig Config) {
+	oldGeneral, err := configGeneral(oldConfig, m.modeName)
+	if err != nil {
+		oldGeneral, _ = configGeneral(oldConfig, "")
+	}
+	newGeneral, err := configGeneral(root.Config, m.modeName)
+	if err != nil {
+		log.Printf("config reload: %s", err)
+		m.modeName = ""
+		newGeneral, _ = configGeneral(root.Config, "")
+	}
+	caption := m.general.Caption
+	if !updateGeneral(&m.general, oldGeneral, newGeneral) {
+		return
+	}
+	m.regexpCompile()
+	m.resetAlign()
+	if m.general.Caption != caption && m.general.Caption != "" {
+		m.Caption = m.general.Caption
+	}
+}
+
+// configGeneral returns the general settings of the mode of the config,
+// including the settings implied by the others.
+func configGeneral(config Config, modeName string) (general, error) {
+	g := config.General
+	if modeName != "" && modeName != "general" {
+		c, ok := config.Mode[modeName]
+		if !ok {
+			return g, fmt.Errorf("%s mode not found", modeName)
+		}
+		g = mergeGeneral(g, c)
+	}
+	if g.FollowName {
+		g.FollowMode = true
+	}
+	if len(g.ColumnPositions) > 0 {
+		g.ColumnWidth = true
+	}
+	if g.ColumnWidth || g.ColumnAlign {
+		g.ColumnMode = true
+	}
+	return g, nil
+}
+
+// updateGeneral sets the fields of dst that differ between oldGeneral and newGeneral to newGeneral.
+// The fields of dst that differ from oldGeneral have been changed interactively, and are kept.
+// It returns true if any field is set.
+func updateGeneral(dst *general, oldGeneral general, newGeneral general) bool {
+	d := reflect.ValueOf(dst).Elem()
+	o, n := reflect.ValueOf(oldGeneral), reflect.ValueOf(newGeneral)
+	updated := false
+	for i := 0; i < d.NumField(); i++ {
+		// The compiled regular expressions are set by regexpCompile.
+		if d.Field(i).Kind() == reflect.Pointer {
+			continue
+		}
+		if equalGeneralValue(o.Field(i), n.Field(i)) || !equalGeneralValue(d.Field(i), o.Field(i)) {
+			continue
+		}
+		d.Field(i).Set(n.Field(i))
+		updated = true
+	}
+	return updated
+}
+
+// equalGeneralValue returns true if the values of the field are equal.
+// The empty slice is equal to nil.
+func equalGeneralValue(a reflect.Value, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
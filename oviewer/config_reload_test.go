@@ -0,0 +1,185 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_applyConfig(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	root.Doc.moveLine(10)
+	root.Doc.modeName = "csv"
+
+	config := NewConfig()
+	config.Keybind = map[string][]string{actionExit: {"Q"}}
+	config.Mode = map[string]general{"csv": {ColumnMode: true, ColumnDelimiter: "\t"}}
+	config.StyleHeader = OVStyle{Foreground: "red"}
+	if err := root.applyConfig(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if got := root.keyBinds[actionExit]; len(got) != 1 || got[0] != "Q" {
+		t.Errorf("keyBinds[exit] = %v, want [Q]", got)
+	}
+	if root.StyleHeader.Foreground != "red" {
+		t.Errorf("StyleHeader = %v, want red", root.StyleHeader)
+	}
+	if !root.Doc.ColumnMode || root.Doc.ColumnDelimiter != "\t" {
+		t.Errorf("ColumnMode = %v, ColumnDelimiter = %q, want the csv mode", root.Doc.ColumnMode, root.Doc.ColumnDelimiter)
+	}
+	if root.Doc.topLN != 10 {
+		t.Errorf("topLN = %d, want 10", root.Doc.topLN)
+	}
+
+	// The invalid key binding keeps the current config.
+	config = NewConfig()
+	config.Keybind = map[string][]string{actionExit: {"ctrl+zz"}}
+	if err := root.applyConfig(ctx, config); err == nil {
+		t.Errorf("applyConfig() error = nil, want error")
+	}
+	if got := root.keyBinds[actionExit]; len(got) != 1 || got[0] != "Q" {
+		t.Errorf("keyBinds[exit] = %v, want [Q]", got)
+	}
+	if root.StyleHeader.Foreground != "red" {
+		t.Errorf("StyleHeader = %v, want red", root.StyleHeader)
+	}
+}
+
+func TestRoot_applyConfigKeepOverride(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	m := root.Doc
+	m.general = root.Config.General
+	// Changed interactively.
+	m.Header = 3
+	m.ColumnMode = true
+	m.ColumnDelimiter = "|"
+	filterDoc := docHelper(t, "filter\n")
+	filterDoc.documentType = DocFilter
+	filterDoc.general = root.Config.General
+	root.DocList = append(root.DocList, filterDoc)
+
+	config := NewConfig()
+	config.General.Header = 1
+	config.General.TabWidth = 4
+	config.General.ColumnDelimiter = ","
+	if err := root.applyConfig(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if m.Header != 3 || !m.ColumnMode || m.ColumnDelimiter != "|" {
+		t.Errorf("Header = %d, ColumnMode = %v, ColumnDelimiter = %q, want the interactive settings", m.Header, m.ColumnMode, m.ColumnDelimiter)
+	}
+	if m.TabWidth != 4 {
+		t.Errorf("TabWidth = %d, want 4", m.TabWidth)
+	}
+	if filterDoc.TabWidth != 8 || filterDoc.Header != 0 {
+		t.Errorf("filter TabWidth = %d, Header = %d, want unchanged", filterDoc.TabWidth, filterDoc.Header)
+	}
+
+	// The settings not changed in the config are kept.
+	m.TabWidth = 2
+	config.General.Header = 2
+	if err := root.applyConfig(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if m.TabWidth != 2 {
+		t.Errorf("TabWidth = %d, want 2", m.TabWidth)
+	}
+}
+
+func TestRoot_reloadConfig(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootHelper(t)
+	errLoad := errors.New("load error")
+	root.WatchConfig([]string{"config.yaml"}, func() (Config, []string, error) {
+		return Config{}, nil, errLoad
+	})
+	root.reloadConfig(context.Background())
+	if root.message != "config reload: load error" {
+		t.Errorf("message = %q, want the load error", root.message)
+	}
+}
+
+func TestRoot_watchConfigEvent(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootHelper(t)
+	if root.watchConfigEvent(fsnotify.Event{Name: "/tmp/config.yaml", Op: fsnotify.Write}) {
+		t.Errorf("watchConfigEvent() = true, want false without WatchConfig")
+	}
+	root.WatchConfig([]string{"/tmp/config.yaml"}, func() (Config, []string, error) {
+		return NewConfig(), []string{"/tmp/config.yaml"}, nil
+	})
+	if root.watchConfigEvent(fsnotify.Event{Name: "/tmp/other.yaml", Op: fsnotify.Write}) {
+		t.Errorf("watchConfigEvent() = true, want false for the other file")
+	}
+	if !root.watchConfigEvent(fsnotify.Event{Name: "/tmp/config.yaml", Op: fsnotify.Write}) {
+		t.Errorf("watchConfigEvent() = false, want true")
+	}
+	if root.configTimer == nil {
+		t.Errorf("configTimer = nil, want the reload timer")
+	}
+	root.configTimer.Stop()
+}
+
+func TestRoot_reloadConfigFiles(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	userDir, includeDir := t.TempDir(), t.TempDir()
+	userFile := filepath.Join(userDir, "config.yaml")
+	includeFile := filepath.Join(includeDir, "include.yaml")
+	root.WatchConfig([]string{userFile}, func() (Config, []string, error) {
+		// The include was added to the user config.
+		return NewConfig(), []string{userFile, includeFile}, nil
+	})
+	root.watchConfig(watcher)
+	root.reloadConfig(ctx)
+	if !root.watchConfigEvent(fsnotify.Event{Name: includeFile, Op: fsnotify.Write}) {
+		t.Errorf("watchConfigEvent() = false, want true for the included file")
+	}
+	root.configTimer.Stop()
+	if !slices.Contains(watcher.WatchList(), includeDir) {
+		t.Errorf("WatchList() = %v, want %s", watcher.WatchList(), includeDir)
+	}
+}
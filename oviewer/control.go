@@ -13,6 +13,8 @@ import (
 type controlSpecifier struct {
 	searcher Searcher
 	done     chan bool
+	// lines receives the lines read by requestRead.
+	lines    chan [][]byte
 	request  request
 	chunkNum int
 }
@@ -30,6 +32,8 @@ const (
 	requestReload   request = "reload"
 	requestLoad     request = "load"
 	requestSearch   request = "search"
+	requestRead     request = "read"
+	requestCSV      request = "csv"
 )
 
 // ControlFile controls file read and loads in chunks.
@@ -44,11 +48,15 @@ func (m *Document) ControlFile(file *os.File) error {
 		log.Println(err)
 	}
 	atomic.StoreInt32(&m.store.eof, 0)
+	m.csv.setReader()
 
 	go func() {
 		reader := bufio.NewReader(r)
 		for sc := range m.ctlCh {
 			reader, err = m.controlFile(sc, reader)
+			if m.scanCSV() {
+				m.requestCSV()
+			}
 			if sc.done != nil {
 				if err != nil {
 					sc.done <- false
@@ -72,6 +80,7 @@ func (m *Document) ControlReader(r io.Reader, reload func() *bufio.Reader) error
 	m.store.setNewLoadChunks(m.memoryLimit)
 	m.seekable = false
 	reader := bufio.NewReader(r)
+	m.csv.setReader()
 
 	go func() {
 		for sc := range m.ctlCh {
@@ -80,6 +89,9 @@ func (m *Document) ControlReader(r io.Reader, reload func() *bufio.Reader) error
 			if err != nil {
 				log.Println(sc.request, err)
 			}
+			if m.scanCSV() {
+				m.requestCSV()
+			}
 			if sc.done != nil {
 				if err != nil {
 					sc.done <- false
@@ -146,6 +158,13 @@ func (m *Document) controlFile(sc controlSpecifier, reader *bufio.Reader) (*bufi
 		return m.loadRead(reader, sc.chunkNum)
 	case requestSearch:
 		return m.searchRead(reader, sc.chunkNum, sc.searcher)
+	case requestRead:
+		lines, err := m.readChunk(sc.chunkNum)
+		sc.lines <- lines
+		return reader, err
+	case requestCSV:
+		// The lines are scanned after each request.
+		return reader, nil
 	case requestReload:
 		reader, err = m.reloadRead(reader)
 		m.requestStart()
@@ -179,6 +198,8 @@ func (m *Document) controlReader(sc controlSpecifier, reader *bufio.Reader, relo
 	case requestClose:
 		log.Println("close")
 		return reader, nil
+	case requestCSV:
+		// The lines are scanned after each request.
 	default:
 		panic(fmt.Sprintf("unexpected %s", sc.request))
 	}
@@ -191,6 +212,7 @@ func (m *Document) controlLog(sc controlSpecifier) {
 	switch sc.request {
 	case requestLoad:
 	case requestFollow:
+	case requestCSV:
 	case requestReload:
 		m.reset()
 	default:
@@ -249,8 +271,33 @@ func (m *Document) requestSearch(chunkNum int, searcher Searcher) bool {
 	return <-sc.done
 }
 
+// requestRead sends instructions to read the chunk from the file without loading it into memory.
+func (m *Document) requestRead(chunkNum int) ([][]byte, error) {
+	sc := controlSpecifier{
+		request:  requestRead,
+		chunkNum: chunkNum,
+		done:     make(chan bool),
+		lines:    make(chan [][]byte, 1),
+	}
+	m.ctlCh <- sc
+	if !<-sc.done {
+		return nil, fmt.Errorf("%w: chunk %d", ErrOutOfChunk, chunkNum)
+	}
+	return <-sc.lines, nil
+}
+
+// requestCSV sends instructions to scan the lines for the states of CSV.
+func (m *Document) requestCSV() {
+	go func() {
+		m.ctlCh <- controlSpecifier{
+			request: requestCSV,
+		}
+	}()
+}
+
 // requestClose sends instructions to close the file.
 func (m *Document) requestClose() bool {
+	m.stopPreprocess()
 	atomic.StoreInt32(&m.store.readCancel, 1)
 	defer atomic.StoreInt32(&m.store.readCancel, 0)
 	sc := controlSpecifier{
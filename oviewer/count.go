@@ -0,0 +1,61 @@
+package oviewer
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// maxCount is the upper limit of the count prefix.
+const maxCount = 1000000
+
+// countKey collects the digits of the count prefix (e.g. 25j, 3n).
+// It returns true if the key was consumed as part of the count.
+// Digits that are assigned to actions (e.g. 9) are only used
+// as a count after the first digit has been entered.
+func (root *Root) countKey(ev *tcell.EventKey) bool {
+	if ev.Key() != tcell.KeyRune || ev.Modifiers()&^tcell.ModShift != 0 {
+		return false
+	}
+	ch := ev.Rune()
+	if ch < '0' || ch > '9' {
+		return false
+	}
+	if root.countPending == 0 {
+		if ch == '0' {
+			return false
+		}
+		if root.keyBound(ev) {
+			return false
+		}
+	}
+	n := root.countPending*10 + int(ch-'0')
+	if n > maxCount {
+		n = maxCount
+	}
+	root.countPending = n
+	return true
+}
+
+// keyBound returns true if the key is assigned to an action.
+func (root *Root) keyBound(ev *tcell.EventKey) bool {
+	for _, keys := range root.keyBinds {
+		for _, k := range singleKeys(keys) {
+			s, err := decodeKeyStroke(k)
+			if err != nil {
+				continue
+			}
+			if s.match(ev) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// repeatCount returns the count prefix for the running action.
+// It returns 1 if no count has been entered.
+func (root *Root) repeatCount() int {
+	if root.count < 1 {
+		return 1
+	}
+	return root.count
+}
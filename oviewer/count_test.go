@@ -0,0 +1,97 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_countKey(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name        string
+		keys        string
+		wantPending int
+		wantConsume bool
+	}{
+		{
+			name:        "testCount",
+			keys:        "25",
+			wantPending: 25,
+			wantConsume: true,
+		},
+		{
+			name:        "testZero",
+			keys:        "0",
+			wantPending: 0,
+			wantConsume: false,
+		},
+		{
+			name:        "testBoundKey",
+			keys:        "9",
+			wantPending: 0,
+			wantConsume: false,
+		},
+		{
+			name:        "testBoundKeyAfterCount",
+			keys:        "19",
+			wantPending: 19,
+			wantConsume: true,
+		},
+		{
+			name:        "testNotDigit",
+			keys:        "j",
+			wantPending: 0,
+			wantConsume: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootHelper(t)
+			if _, err := root.setKeyConfig(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			var got bool
+			for _, r := range tt.keys {
+				got = root.countKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+			if got != tt.wantConsume {
+				t.Errorf("Root.countKey() = %v, want %v", got, tt.wantConsume)
+			}
+			if root.countPending != tt.wantPending {
+				t.Errorf("Root.countKey() pending = %v, want %v", root.countPending, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestRoot_keyCaptureCount(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	root.draw(ctx)
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, '5', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if root.Doc.topLN != 5 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 5)
+	}
+	if root.countPending != 0 || root.count != 0 {
+		t.Errorf("count is not reset: pending %v, count %v", root.countPending, root.count)
+	}
+	root.keyCapture(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if root.Doc.topLN != 6 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 6)
+	}
+}
@@ -0,0 +1,277 @@
+package oviewer
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// csvQuote is the quote character of CSV (RFC 4180).
+const csvQuote = '"'
+
+// csvState is the state of the CSV parser at the beginning of a line.
+type csvState struct {
+	// quoted is true if the line starts inside a quoted field of the previous line.
+	quoted bool
+	// column is the column number at the beginning of the line.
+	column int
+}
+
+// csvColumns holds the states of the lines that start inside a quoted field.
+// The state of a line depends on all the previous lines,
+// so the lines are scanned chunk by chunk in the goroutine of the reader (see scanCSV),
+// and the draw only looks up the states.
+type csvColumns struct {
+	mu sync.Mutex
+	// reader is true if the goroutine of the reader scans the lines (see ControlFile and ControlReader).
+	reader bool
+	// enabled is true if the lines are scanned with the delimiter.
+	enabled bool
+	// delimiter is the delimiter of the scan.
+	delimiter string
+	// delimiterReg is the regular expression delimiter of the scan.
+	delimiterReg *regexp.Regexp
+	// states is the states of the lines that start inside a quoted field.
+	// The other lines start with the zero value.
+	states map[int]csvState
+	// scanned is the number of lines whose next states are known.
+	scanned int
+	// last is the state at the beginning of the line scanned.
+	last csvState
+	// generation is incremented by reset, to discard the scan in progress.
+	generation int
+}
+
+// reset discards the states and stops the scan until the next lookup.
+func (c *csvColumns) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = false
+	c.states = nil
+	c.scanned = 0
+	c.last = csvState{}
+	c.generation++
+}
+
+// setReader sets the lines to be scanned by the goroutine of the reader.
+func (c *csvColumns) setReader() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reader = true
+}
+
+// isCSV returns true if the columns are parsed as CSV.
+// It is enabled for "," and tab, or by ColumnCSV.
+func (m *Document) isCSV() bool {
+	if m.ColumnCSV {
+		return true
+	}
+	if m.ColumnDelimiterReg != nil {
+		return false
+	}
+	return m.ColumnDelimiter == "," || m.ColumnDelimiter == "\t"
+}
+
+// columnIndexes returns the positions of the column delimiters of the line
+// and the column number at the beginning of the line.
+// In CSV, the delimiters in the quoted fields are excluded,
+// and the line following a line break in a quoted field continues the column.
+func (m *Document) columnIndexes(lN int, str string) ([][]int, int) {
+	if !m.isCSV() {
+		return allIndex(str, m.ColumnDelimiter, m.ColumnDelimiterReg), 0
+	}
+	state := m.csvLineState(lN)
+	indexes, _ := csvIndexes(str, m.ColumnDelimiter, m.ColumnDelimiterReg, state.quoted)
+	return indexes, state.column
+}
+
+// columnStarts returns the start positions of the columns of the line (see delimiterWidths).
+// It returns nil for the line that starts inside a quoted field,
+// because the columns of the line do not start at the beginning of the line.
+func (m *Document) columnStarts(lN int, str string) []int {
+	indexes, column := m.columnIndexes(lN, str)
+	if column > 0 {
+		return nil
+	}
+	if m.isColumnLayout() {
+		return m.layoutStarts(lN, str)
+	}
+	return delimiterWidths(str, indexes)
+}
+
+// csvLineState returns the state at the beginning of the line.
+// The lines that have not been scanned yet are treated as not quoted.
+// If the delimiter has changed, the reader is requested to scan the lines again.
+func (m *Document) csvLineState(lN int) csvState {
+	c := &m.csv
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled || c.delimiter != m.ColumnDelimiter || c.delimiterReg != m.ColumnDelimiterReg {
+		c.enabled = true
+		c.delimiter, c.delimiterReg = m.ColumnDelimiter, m.ColumnDelimiterReg
+		c.states = nil
+		c.scanned = 0
+		c.last = csvState{}
+		c.generation++
+		if c.reader {
+			m.requestCSV()
+		} else {
+			go m.scanCSVAll()
+		}
+		return csvState{}
+	}
+	if lN > c.scanned {
+		return csvState{}
+	}
+	return c.states[lN]
+}
+
+// scanCSV scans the lines of the chunk following the lines scanned.
+// It is called in the goroutine of the reader,
+// so the chunks that are not loaded are read from the file without loading them.
+// It returns true if the next chunk remains to be scanned.
+func (m *Document) scanCSV() bool {
+	c := &m.csv
+	c.mu.Lock()
+	enabled, delimiter, delimiterReg := c.enabled, c.delimiter, c.delimiterReg
+	scanned, last, generation := c.scanned, c.last, c.generation
+	c.mu.Unlock()
+	if !enabled || scanned >= m.BufEndNum() {
+		return false
+	}
+
+	chunkNum := scanned / ChunkSize
+	lines, err := m.csvChunkLines(chunkNum)
+	if err != nil {
+		log.Printf("scan csv: %s", err)
+		return false
+	}
+	start := chunkNum * ChunkSize
+	end := start + len(lines)
+	if lines == nil {
+		// The chunk has been freed from memory, and the states of the lines are unknown.
+		_, chunkEnd := m.store.chunkRange(chunkNum)
+		end = min(start+chunkEnd, m.BufEndNum())
+		last = csvState{}
+	}
+	var states map[int]csvState
+	for lN := scanned; lN < start+len(lines); lN++ {
+		line := lines[lN-start]
+		// The last line without a newline may be continued.
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			end = lN
+			break
+		}
+		last = csvNextState(string(line[:len(line)-1]), last, delimiter, delimiterReg)
+		if last.quoted {
+			if states == nil {
+				states = make(map[int]csvState)
+			}
+			states[lN+1] = last
+		}
+	}
+	if end <= scanned {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation || c.scanned != scanned {
+		return false
+	}
+	if c.states == nil {
+		c.states = make(map[int]csvState, len(states))
+	}
+	for lN, state := range states {
+		c.states[lN] = state
+	}
+	c.scanned = end
+	c.last = last
+	atomic.StoreInt32(&m.store.changed, 1)
+	return end == start+ChunkSize && end < m.BufEndNum()
+}
+
+// scanCSVAll scans all the lines that have been read.
+// It is used for the document read by ReadAll, which has no goroutine of the reader.
+func (m *Document) scanCSVAll() {
+	for m.scanCSV() {
+	}
+}
+
+// csvChunkLines returns the lines of the chunk for scanCSV.
+// It returns nil if the chunk has been freed from memory.
+func (m *Document) csvChunkLines(chunkNum int) ([][]byte, error) {
+	_, end := m.store.chunkRange(chunkNum)
+	lines := m.store.chunkLines(chunkNum)
+	if len(lines) >= end || !m.seekable || m.file == nil {
+		return lines, nil
+	}
+	if m.checkClose() {
+		return nil, ErrAlreadyClose
+	}
+	return m.readChunk(chunkNum)
+}
+
+// csvNextState returns the state at the beginning of the next line.
+func csvNextState(str string, state csvState, delimiter string, delimiterReg *regexp.Regexp) csvState {
+	if !state.quoted && strings.IndexByte(str, csvQuote) < 0 {
+		return csvState{}
+	}
+	indexes, quoted := csvIndexes(str, delimiter, delimiterReg, state.quoted)
+	if !quoted {
+		return csvState{}
+	}
+	return csvState{quoted: true, column: state.column + len(indexes)}
+}
+
+// csvIndexes returns the positions of the delimiters outside the quoted fields (RFC 4180),
+// and whether the end of the line is inside a quoted field.
+// A field is quoted if it starts with a quote, and a quote in the quoted field is escaped by doubling it.
+// quoted is true if the line starts inside a quoted field.
+func csvIndexes(str string, delimiter string, delimiterReg *regexp.Regexp, quoted bool) ([][]int, bool) {
+	all := allIndex(str, delimiter, delimiterReg)
+	if !quoted && strings.IndexByte(str, csvQuote) < 0 {
+		return all, false
+	}
+
+	indexes := make([][]int, 0, len(all))
+	fieldStart := 0
+	if quoted {
+		fieldStart = -1
+	}
+	pos, i := 0, 0
+	for pos < len(str) {
+		if quoted {
+			q := strings.IndexByte(str[pos:], csvQuote)
+			if q < 0 {
+				return indexes, true
+			}
+			pos += q + 1
+			// Escaped quote.
+			if pos < len(str) && str[pos] == csvQuote {
+				pos++
+				continue
+			}
+			quoted = false
+			continue
+		}
+		if pos == fieldStart && str[pos] == csvQuote {
+			quoted = true
+			pos++
+			continue
+		}
+		for i < len(all) && all[i][0] < pos {
+			i++
+		}
+		if i >= len(all) {
+			break
+		}
+		indexes = append(indexes, all[i])
+		pos = all[i][1]
+		fieldStart = pos
+		i++
+	}
+	return indexes, quoted
+}
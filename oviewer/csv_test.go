@@ -0,0 +1,297 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// csvScanHelper waits for the reader to scan the lines of the document for the states of CSV.
+func csvScanHelper(t *testing.T, m *Document) {
+	t.Helper()
+	m.csvLineState(0)
+	for i := 0; i < 100; i++ {
+		m.csv.mu.Lock()
+		scanned := m.csv.scanned
+		m.csv.mu.Unlock()
+		if m.BufEOF() && scanned >= m.BufEndNum()-1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("csv scan timeout")
+}
+
+func Test_csvIndexes(t *testing.T) {
+	type args struct {
+		str       string
+		delimiter string
+		quoted    bool
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       [][]int
+		wantQuoted bool
+	}{
+		{
+			name: "noQuote",
+			args: args{str: "a,b,c", delimiter: ","},
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "quotedDelimiter",
+			args: args{str: `1,"Smith, John",x`, delimiter: ","},
+			want: [][]int{{1, 2}, {15, 16}},
+		},
+		{
+			name: "escapedQuote",
+			args: args{str: `"say ""a,b""",x`, delimiter: ","},
+			want: [][]int{{13, 14}},
+		},
+		{
+			name: "quoteInField",
+			args: args{str: `ab"c,d`, delimiter: ","},
+			want: [][]int{{4, 5}},
+		},
+		{
+			name:       "openQuote",
+			args:       args{str: `2,"first line`, delimiter: ","},
+			want:       [][]int{{1, 2}},
+			wantQuoted: true,
+		},
+		{
+			name:       "continued",
+			args:       args{str: "second, line", delimiter: ",", quoted: true},
+			want:       [][]int{},
+			wantQuoted: true,
+		},
+		{
+			name: "closed",
+			args: args{str: `third line",x`, delimiter: ",", quoted: true},
+			want: [][]int{{11, 12}},
+		},
+		{
+			name: "tab",
+			args: args{str: "\"a\tb\"\tc", delimiter: "\t"},
+			want: [][]int{{5, 6}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotQuoted := csvIndexes(tt.args.str, tt.args.delimiter, nil, tt.args.quoted)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("csvIndexes() got = %v, want %v", got, tt.want)
+			}
+			if gotQuoted != tt.wantQuoted {
+				t.Errorf("csvIndexes() quoted = %v, want %v", gotQuoted, tt.wantQuoted)
+			}
+		})
+	}
+}
+
+func TestDocument_isCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		columnCSV bool
+		want      bool
+	}{
+		{name: "comma", delimiter: ",", want: true},
+		{name: "tab", delimiter: "\t", want: true},
+		{name: "bar", delimiter: "|", want: false},
+		{name: "explicit", delimiter: ";", columnCSV: true, want: true},
+		{name: "regexp", delimiter: "/,/", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, "")
+			m.setDelimiter(tt.delimiter)
+			m.ColumnCSV = tt.columnCSV
+			if got := m.isCSV(); got != tt.want {
+				t.Errorf("Document.isCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_columnIndexes(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "quoted.csv"))
+	m := root.Doc
+	m.setDelimiter(",")
+	csvScanHelper(t, m)
+	tests := []struct {
+		lN         int
+		want       [][]int
+		wantColumn int
+	}{
+		{lN: 1, want: [][]int{{1, 2}, {15, 16}}, wantColumn: 0},
+		{lN: 2, want: [][]int{{1, 2}, {7, 8}}, wantColumn: 0},
+		{lN: 3, want: [][]int{}, wantColumn: 2},
+		{lN: 4, want: [][]int{{11, 12}}, wantColumn: 2},
+		{lN: 5, want: [][]int{{1, 2}, {7, 8}}, wantColumn: 0},
+	}
+	for _, tt := range tests {
+		str, err := m.LineStr(tt.lN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, gotColumn := m.columnIndexes(tt.lN, str)
+		if !reflect.DeepEqual(got, tt.want) || gotColumn != tt.wantColumn {
+			t.Errorf("Document.columnIndexes(%d) = %v, %d, want %v, %d", tt.lN, got, gotColumn, tt.want, tt.wantColumn)
+		}
+	}
+	if got := m.columnStarts(3, "second, line"); got != nil {
+		t.Errorf("Document.columnStarts() = %v, want nil", got)
+	}
+}
+
+func TestRoot_columnDelimiterHighlightCSV(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "quoted.csv"))
+	m := root.Doc
+	m.setDelimiter(",")
+	csvScanHelper(t, m)
+	m.columnCursor = 2
+	root.StyleColumnHighlight = OVStyle{Bold: true}
+	columnHighlight := tcell.StyleDefault.Bold(true)
+
+	// The quoted comma does not split the column.
+	line := m.getLineC(1, m.TabWidth)
+	root.columnDelimiterHighlight(1, line)
+	if line.lc[16].style != columnHighlight {
+		t.Errorf("style got: %v want: %v", line.lc[16].style, columnHighlight)
+	}
+	if line.lc[8].style == columnHighlight {
+		t.Errorf("style got: %v, want not highlighted", line.lc[8].style)
+	}
+	// The line in the quoted field continues the column.
+	line = m.getLineC(3, m.TabWidth)
+	root.columnDelimiterHighlight(3, line)
+	if line.lc[0].style != columnHighlight || line.lc[10].style != columnHighlight {
+		t.Errorf("style got: %v want: %v", line.lc[0].style, columnHighlight)
+	}
+}
+
+func TestDocument_scanCSV(t *testing.T) {
+	// The quoted field spans the chunks, and the chunk after the first is not loaded.
+	var b strings.Builder
+	for i := 0; i < ChunkSize-1; i++ {
+		fmt.Fprintf(&b, "%d,a\n", i)
+	}
+	b.WriteString("\"x\ny\",b\n")
+	for i := ChunkSize + 1; i < ChunkSize+100; i++ {
+		fmt.Fprintf(&b, "%d,a\n", i)
+	}
+	b.WriteString("1,\"z\n")
+	b.WriteString("z\"\n")
+	fileName := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := docFileReadHelper(t, fileName)
+	m.setDelimiter(",")
+	csvScanHelper(t, m)
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Fatal("chunk 1 is loaded")
+	}
+	tests := []struct {
+		lN   int
+		want csvState
+	}{
+		{lN: ChunkSize - 1, want: csvState{}},
+		{lN: ChunkSize, want: csvState{quoted: true, column: 0}},
+		{lN: ChunkSize + 1, want: csvState{}},
+		{lN: ChunkSize + 101, want: csvState{quoted: true, column: 1}},
+	}
+	for _, tt := range tests {
+		if got := m.csvLineState(tt.lN); got != tt.want {
+			t.Errorf("Document.csvLineState(%d) = %v, want %v", tt.lN, got, tt.want)
+		}
+	}
+	// The states are kept when the cache is cleared.
+	m.ClearCache()
+	if got := m.csvLineState(ChunkSize); !got.quoted {
+		t.Errorf("Document.csvLineState() = %v, want quoted after ClearCache", got)
+	}
+}
+
+// The lines of a record with a line break in the quoted field stay on the lines of the file,
+// and the lines after the line break continue the columns of the record.
+func TestRoot_csvContinuationLines(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "quoted.csv"))
+	m := root.Doc
+	m.ColumnMode = true
+	m.setDelimiter(",")
+	csvScanHelper(t, m)
+
+	// Column numbers.
+	values := []struct {
+		lN     int
+		column int
+		want   string
+		wantOK bool
+	}{
+		{lN: 2, column: 2, want: "\"first line", wantOK: true},
+		{lN: 3, column: 2, want: "second, line", wantOK: true},
+		{lN: 3, column: 0, wantOK: false},
+		{lN: 4, column: 2, want: "third line\"", wantOK: true},
+		{lN: 4, column: 3, want: "x", wantOK: true},
+		{lN: 5, column: 1, want: "plain", wantOK: true},
+	}
+	for _, tt := range values {
+		line := m.getLineC(tt.lN, m.TabWidth)
+		got, ok := m.columnValue(tt.lN, line, tt.column)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Document.columnValue(%d, %d) = %q, %v, want %q, %v", tt.lN, tt.column, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Highlighting of the line that ends the quoted field and the following line.
+	root.StyleColumnHighlight = OVStyle{Bold: true}
+	columnHighlight := tcell.StyleDefault.Bold(true)
+	m.columnCursor = 3
+	line := m.getLineC(4, m.TabWidth)
+	root.columnDelimiterHighlight(4, line)
+	if line.lc[12].style != columnHighlight || line.lc[0].style == columnHighlight {
+		t.Errorf("highlight of line 4 = %v, %v", line.lc[12].style, line.lc[0].style)
+	}
+	m.columnCursor = 2
+	line = m.getLineC(5, m.TabWidth)
+	root.columnDelimiterHighlight(5, line)
+	if line.lc[8].style != columnHighlight || line.lc[2].style == columnHighlight {
+		t.Errorf("highlight of line 5 = %v, %v", line.lc[8].style, line.lc[2].style)
+	}
+
+	// The cursor moves by the columns of the line that starts a record.
+	root.prepareScreen()
+	ctx := context.Background()
+	m.topLN = 3
+	m.width = 80
+	root.prepareDraw(ctx)
+	if got := m.rightmostColumn(root.scr); got != 2 {
+		t.Errorf("Document.rightmostColumn() = %d, want 2", got)
+	}
+	m.columnCursor = 0
+	if _, cursor, err := m.moveToDelimiter(root.scr, 1); err != nil || cursor != 1 {
+		t.Errorf("Document.moveToDelimiter() = %d, %v, want 1, nil", cursor, err)
+	}
+}
@@ -40,6 +40,7 @@ func (root *Root) addDocument(ctx context.Context, m *Document) {
 	root.setMessageLogf("add %s", m.FileName)
 	m.general = root.Config.General
 	m.regexpCompile()
+	root.applyModeRule(m)
 
 	root.mu.Lock()
 	defer root.mu.Unlock()
@@ -93,7 +94,7 @@ func (root *Root) closeAllDocument(ctx context.Context, dType documentType) {
 
 // nextDoc displays the next document.
 func (root *Root) nextDoc(ctx context.Context) {
-	root.setDocumentNum(ctx, root.CurrentDoc+1)
+	root.setDocumentNum(ctx, root.CurrentDoc+root.repeatCount())
 	root.input.Event = normal()
 	root.debugMessage("next document")
 }
@@ -143,6 +144,7 @@ func (root *Root) setDocumentNum(ctx context.Context, docNum int) {
 
 // setDocument sets the Document.
 func (root *Root) setDocument(ctx context.Context, m *Document) {
+	root.endVisual()
 	root.Doc = m
 	root.ViewSync(ctx)
 }
@@ -2,6 +2,7 @@ package oviewer
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,10 @@ const (
 	DocHelp
 	DocLog
 	DocFilter
+	DocSort
+	DocStats
+	DocRecord
+	DocExport
 )
 
 type documentType int
@@ -57,13 +62,27 @@ type Document struct {
 	FileName string
 	// Caption is an additional caption to display after the file name.
 	Caption string
+	// commandLine is the command line whose output is the document.
+	commandLine string
+	// modeName is the name of the view mode applied to the document.
+	modeName string
+	// csv is the states of the CSV parser.
+	csv csvColumns
+	// preprocess is the running preprocessor.
+	preprocess runningPreprocess
 	// filepath stores the absolute pathname for file watching.
 	filepath string
 
 	// marked is a list of marked line numbers.
 	marked []int
+	// letterMarks is the line numbers marked with letters.
+	letterMarks map[rune]int
 	// columnWidths is a slice of column widths.
 	columnWidths []int
+	// alignWidths is a slice of the widths of the aligned columns.
+	alignWidths []int
+	// alignKey is the state when alignWidths is computed.
+	alignKey alignKey
 
 	// status is the display status of the document.
 	general
@@ -106,9 +125,15 @@ type Document struct {
 	x int
 	// columnCursor is the number of columns.
 	columnCursor int
+	// columnSelected is true if the column of the Column setting has been selected.
+	columnSelected bool
 
 	// lastSearchLN is the last search line number.
 	lastSearchLN int
+	// reloadTopLN is the top line number to restore after reloading.
+	reloadTopLN int
+	// filterSearcher is the searcher of the filter document.
+	filterSearcher Searcher
 	// showGotoF displays the specified line if it is true.
 	showGotoF bool
 
@@ -192,6 +217,10 @@ type LineC struct {
 	pos widthPos
 	// valid is true if the line is valid.
 	valid bool
+	// columns is the ranges of the columns on the screen when the columns are laid out.
+	columns [][2]int
+	// frozen is the width of the frozen columns.
+	frozen int
 	// The number of the section in the screen.
 	section int
 	// Line number within a section.
@@ -314,6 +343,58 @@ func (m *Document) Line(n int) ([]byte, error) {
 	return s.GetChunkLine(chunkNum, cn)
 }
 
+// chunkLines returns the lines of the chunk without changing the loaded chunks.
+// The chunk of the file that is not in memory is read from the file.
+// The lines include the newline.
+func (m *Document) chunkLines(chunkNum int) ([][]byte, error) {
+	if m.store.lastChunkNum() < chunkNum {
+		return nil, fmt.Errorf("%w: chunk %d", ErrOutOfChunk, chunkNum)
+	}
+	_, end := m.store.chunkRange(chunkNum)
+	lines := m.store.chunkLines(chunkNum)
+	if len(lines) >= end {
+		return lines[:end], nil
+	}
+	if !m.seekable {
+		// The chunk has been freed from memory.
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("%w: chunk %d", ErrOutOfChunk, chunkNum)
+		}
+		return lines, nil
+	}
+	return m.requestRead(chunkNum)
+}
+
+// eachLine calls fn for each line from start to end (not included).
+// Unlike Line, the chunks that are not loaded are read without loading them into memory,
+// so it can be called outside the event loop.
+func (m *Document) eachLine(ctx context.Context, start int, end int, fn func(lN int, line []byte) error) error {
+	for chunkNum := start / ChunkSize; chunkNum*ChunkSize < end; chunkNum++ {
+		select {
+		case <-ctx.Done():
+			return ErrCancel
+		default:
+		}
+		lines, err := m.chunkLines(chunkNum)
+		if err != nil {
+			return err
+		}
+		for cn, line := range lines {
+			lN := chunkNum*ChunkSize + cn
+			if lN < start {
+				continue
+			}
+			if lN >= end {
+				return nil
+			}
+			if err := fn(lN, bytes.TrimSuffix(line, []byte("\n"))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetChunkLine returns one line from buffer.
 func (s *store) GetChunkLine(chunkNum int, cn int) ([]byte, error) {
 	s.mu.Lock()
@@ -426,6 +507,9 @@ func (m *Document) getLineC(lN int, tabWidth int) LineC {
 		copy(lc, line.lc)
 		line.lc = lc
 		line.valid = true
+		if m.isLayoutLine() {
+			return m.layoutLine(lN, line)
+		}
 		return line
 	}
 
@@ -450,10 +534,13 @@ func (m *Document) getLineC(lN int, tabWidth int) LineC {
 		m.cache.Add(lN, line)
 	}
 
+	line.valid = true
+	if m.isLayoutLine() {
+		return m.layoutLine(lN, line)
+	}
 	lc := make(contents, len(org))
 	copy(lc, org)
 	line.lc = lc
-	line.valid = true
 	return line
 }
 
@@ -492,6 +579,7 @@ func (m *Document) regexpCompile() {
 func (m *Document) setDelimiter(delm string) {
 	m.ColumnDelimiter = delm
 	m.ColumnDelimiterReg = condRegexpCompile(delm)
+	m.resetAlign()
 }
 
 // setSectionDelimiter sets the document section delimiter.
@@ -507,8 +595,13 @@ func (m *Document) setMultiColorWords(words []string) {
 }
 
 // setColumnWidths sets the column widths.
-// Guess the width of the columns using the screen contents.
+// The positions of ColumnPositions are used if specified,
+// otherwise guess the width of the columns using the screen contents.
 func (m *Document) setColumnWidths(scr SCR) {
+	if len(m.ColumnPositions) > 0 {
+		m.columnWidths = positionWidths(m.ColumnPositions)
+		return
+	}
 	if len(scr.lines) == 0 {
 		return
 	}
@@ -41,6 +41,12 @@ func (root *Root) draw(ctx context.Context) {
 	if root.scr.mouseSelect {
 		root.drawSelect(root.scr.x1, root.scr.y1, root.scr.x2, root.scr.y2, true)
 	}
+	if root.visual.active {
+		root.drawVisual()
+	}
+	if root.input.Event.Mode() == ColumnPositions {
+		root.drawRuler()
+	}
 
 	root.drawStatus()
 	root.Show()
@@ -185,15 +191,24 @@ func (root *Root) drawWrapLine(y int, lX int, lN int, lc contents) (int, int) {
 // drawNoWrapLine draws contents without wrapping and returns the next drawing position.
 func (root *Root) drawNoWrapLine(y int, startX int, lN int, lc contents) (int, int) {
 	startX = max(startX, root.minStartX)
+	// The frozen columns are not scrolled.
+	frozen := 0
+	if startX > 0 {
+		frozen = root.scr.lines[lN].frozen
+	}
 	for x := 0; root.scr.startX+x < root.scr.vWidth; x++ {
-		if startX+x >= len(lc) {
+		lx := startX + x
+		if x < frozen {
+			lx = x
+		}
+		if lx >= len(lc) {
 			// EOL
 			root.clearEOL(root.scr.startX+x, y)
 			break
 		}
 		content := DefaultContent
-		if startX+x >= 0 {
-			content = lc[startX+x]
+		if lx >= 0 {
+			content = lc[lx]
 		}
 		root.Screen.SetContent(root.scr.startX+x, y, content.mainc, content.combc, content.style)
 	}
@@ -0,0 +1,165 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultEditorLineArg is the default argument template of the line number.
+const defaultEditorLineArg = "+{line}"
+
+// openEditor opens the current file in the editor at the current line.
+// The document is reloaded after the editor exits.
+// If the current document is a filter of the file, the filter is applied again.
+func (root *Root) openEditor(context.Context) {
+	view := root.Doc
+	m, lN, err := editTarget(view)
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	editor := editorCommand()
+	args := editorArgs(root.Config.EditorLineArg, m.FileName, lN+1)
+	log.Printf("edit %s %s", editor, strings.Join(args, " "))
+	if err := root.editorSuspendResume(editor, args); err != nil {
+		root.setMessageLogf("editor: %s", err)
+		return
+	}
+
+	topLN := m.topLN
+	root.reload(m)
+	// The lines are read again after the reload, so the position is restored in restoreReloadLine.
+	m.reloadTopLN = topLN
+	root.reloadDoc = m
+	root.reloadFilter = nil
+	if view.documentType == DocFilter && view.parent == m && view.filterSearcher != nil {
+		root.reloadFilter = view
+	}
+}
+
+// restoreReloadLine moves the reloaded document to the top line before reloading,
+// when the lines up to the screen have been read again.
+// The filter is applied again when all the lines have been read.
+func (root *Root) restoreReloadLine(ctx context.Context) {
+	m := root.reloadDoc
+	if m == nil {
+		return
+	}
+	if !m.BufEOF() {
+		if root.reloadFilter != nil || m.BufEndNum() <= m.reloadTopLN+m.height {
+			return
+		}
+	}
+	m.moveLine(m.reloadTopLN)
+	m.reloadTopLN = 0
+	root.reloadDoc = nil
+
+	filter := root.reloadFilter
+	if filter == nil {
+		return
+	}
+	root.reloadFilter = nil
+	root.refilter(ctx, m, filter)
+}
+
+// refilter replaces the filter document with a new filter of the reloaded document.
+func (root *Root) refilter(ctx context.Context, m *Document, filter *Document) {
+	root.mu.Lock()
+	for i, doc := range root.DocList {
+		if doc == filter {
+			doc.requestClose()
+			root.DocList = append(root.DocList[:i], root.DocList[i+1:]...)
+			break
+		}
+	}
+	root.mu.Unlock()
+
+	root.Doc = m
+	m.nonMatch = filter.nonMatch
+	root.filterDocument(ctx, filter.filterSearcher)
+}
+
+// editTarget returns the document of the file and the line number to edit.
+// The filter document returns the original document and line number.
+func editTarget(m *Document) (*Document, int, error) {
+	lN := m.topLN + m.firstLine()
+	for m.parent != nil {
+		if m.lineNumMap != nil {
+			if n, ok := m.lineNumMap.LoadForward(lN); ok {
+				lN = n
+			}
+		}
+		m = m.parent
+	}
+	if m.FileName == "" || !m.reopenable {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotEditable, m.FileName)
+	}
+	return m, lN, nil
+}
+
+// editorCommand returns $VISUAL or $EDITOR.
+func editorCommand() string {
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// editorArgs returns the arguments of the editor.
+// {line} in the template is replaced by the line number and {file} by the file name.
+// If the template does not contain {file}, the file name is added at the end.
+func editorArgs(template string, fileName string, lineNum int) []string {
+	if template == "" {
+		template = defaultEditorLineArg
+	}
+	line := strconv.Itoa(lineNum)
+	fields := strings.Fields(template)
+	args := make([]string, 0, len(fields)+1)
+	hasFile := false
+	for _, f := range fields {
+		if strings.Contains(f, "{file}") {
+			hasFile = true
+		}
+		f = strings.ReplaceAll(f, "{line}", line)
+		f = strings.ReplaceAll(f, "{file}", fileName)
+		args = append(args, f)
+	}
+	if !hasFile {
+		args = append(args, fileName)
+	}
+	return args
+}
+
+// editorSuspendResume suspends the screen and runs the editor.
+func (root *Root) editorSuspendResume(editor string, args []string) error {
+	// The editor may contain arguments (e.g. "code -w").
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return ErrNoEditor
+	}
+	if err := root.Screen.Suspend(); err != nil {
+		return err
+	}
+
+	c := exec.Command(fields[0], append(fields[1:], args...)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	runErr := c.Run()
+	if err := root.Screen.Resume(); err != nil {
+		return err
+	}
+	return runErr
+}
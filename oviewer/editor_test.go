@@ -0,0 +1,148 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/noborus/ov/biomap"
+)
+
+func Test_editorArgs(t *testing.T) {
+	type args struct {
+		template string
+		fileName string
+		lineNum  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "default",
+			args: args{template: "", fileName: "test.txt", lineNum: 10},
+			want: []string{"+10", "test.txt"},
+		},
+		{
+			name: "file",
+			args: args{template: "-g {file}:{line}", fileName: "test.txt", lineNum: 3},
+			want: []string{"-g", "test.txt:3"},
+		},
+		{
+			name: "lineOption",
+			args: args{template: "--line {line}", fileName: "test.txt", lineNum: 1},
+			want: []string{"--line", "1", "test.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editorArgs(tt.args.template, tt.args.fileName, tt.args.lineNum); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("editorArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_editTarget(t *testing.T) {
+	m, err := OpenDocument(filepath.Join(testdata, "normal.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.topLN = 5
+	got, lN, err := editTarget(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != m || lN != 5 {
+		t.Errorf("editTarget() = %v, %v, want %v, %v", got.FileName, lN, m.FileName, 5)
+	}
+
+	filterDoc, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	filterDoc.parent = m
+	filterDoc.lineNumMap = biomap.NewMap[int, int]()
+	filterDoc.lineNumMap.Store(2, 30)
+	filterDoc.topLN = 2
+	got, lN, err = editTarget(filterDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != m || lN != 30 {
+		t.Errorf("editTarget() = %v, %v, want %v, %v", got.FileName, lN, m.FileName, 30)
+	}
+
+	stdin, err := NewDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := editTarget(stdin); !errors.Is(err, ErrNotEditable) {
+		t.Errorf("editTarget() error = %v, want %v", err, ErrNotEditable)
+	}
+}
+
+func TestRoot_openEditor(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	t.Setenv("VISUAL", "true")
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	m := root.Doc
+	m.height = 10
+	m.topLN = 20
+	root.openEditor(context.Background())
+	if m.reloadTopLN != 20 {
+		t.Fatalf("openEditor() reloadTopLN = %d, want 20", m.reloadTopLN)
+	}
+	eofHelper(t, m)
+	// The position is restored when the lines have been read again.
+	root.restoreReloadLine(context.Background())
+	if m.topLN != 20 || m.reloadTopLN != 0 || root.reloadDoc != nil {
+		t.Errorf("restoreReloadLine() topLN = %d, reloadTopLN = %d, want 20, 0", m.topLN, m.reloadTopLN)
+	}
+}
+
+func TestRoot_openEditorFilter(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	t.Setenv("VISUAL", "true")
+	ctx := context.Background()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	m := root.Doc
+	root.filter(ctx, "a")
+	filter := root.Doc
+	if filter.documentType != DocFilter {
+		t.Fatalf("filter() documentType = %v, want %v", filter.documentType, DocFilter)
+	}
+	eofHelper(t, filter)
+	root.openEditor(ctx)
+	if root.reloadDoc != m || root.reloadFilter != filter {
+		t.Fatalf("openEditor() reloadDoc = %v, reloadFilter = %v", root.reloadDoc, root.reloadFilter)
+	}
+	eofHelper(t, m)
+	root.restoreReloadLine(ctx)
+	if root.reloadDoc != nil || root.reloadFilter != nil {
+		t.Fatal("restoreReloadLine() did not finish")
+	}
+	// The filter document is replaced by the filter of the reloaded document.
+	if root.Doc == filter || root.Doc.documentType != DocFilter || root.Doc.parent != m {
+		t.Fatalf("restoreReloadLine() did not apply the filter again")
+	}
+	for _, doc := range root.DocList {
+		if doc == filter {
+			t.Errorf("restoreReloadLine() the old filter document remains")
+		}
+	}
+	eofHelper(t, root.Doc)
+	if root.Doc.BufEndNum() != filter.BufEndNum() {
+		t.Errorf("restoreReloadLine() filter lines = %d, want %d", root.Doc.BufEndNum(), filter.BufEndNum())
+	}
+}
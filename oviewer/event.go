@@ -41,6 +41,9 @@ func (root *Root) event(ctx context.Context, ev tcell.Event) bool {
 		// Help and logDoc return to Doc display.
 		root.toNormal(ctx)
 	case *eventReload:
+		if ev.m == nil {
+			ev.m = root.Doc
+		}
 		root.reload(ev.m)
 	case *eventAppSuspend:
 		root.suspend(ctx)
@@ -62,12 +65,16 @@ func (root *Root) event(ctx context.Context, ev tcell.Event) bool {
 		root.setViewMode(ctx, ev.value)
 	case *eventInputSearch:
 		root.firstSearch(ctx, ev.searchType)
+	case *eventFilter:
+		root.filterEvent(ctx, ev)
 	case *eventSearch:
 		root.forwardSearch(ctx, ev.str, 0)
+	case *eventKeySequenceTimeout:
+		root.keySequenceTimeout(ev.gen)
 	case *eventNextSearch:
-		root.forwardSearch(ctx, ev.str, 1)
+		root.nextSearch(ctx, ev.str, true, ev.count)
 	case *eventNextBackSearch:
-		root.backSearch(ctx, ev.str, -1)
+		root.nextSearch(ctx, ev.str, false, ev.count)
 	case *eventSearchMove:
 		root.searchGo(ctx, ev.value)
 	case *eventGoto:
@@ -93,9 +100,45 @@ func (root *Root) event(ctx context.Context, ev tcell.Event) bool {
 	case *eventJumpTarget:
 		root.setJumpTarget(ev.value)
 	case *eventSaveBuffer:
-		root.saveBuffer(ev.value)
+		if ev.text != "" {
+			root.saveString(ev.value, ev.text)
+		} else {
+			root.saveBuffer(ev.value)
+		}
 	case *eventSectionNum:
 		root.setSectionNum(ev.value)
+	case *eventCommand:
+		root.runCommand(ctx, ev.value)
+	case *eventMacroRecord:
+		root.startMacroRecord(ev.value)
+	case *eventMacroPlay:
+		root.playMacro(ev.value)
+	case *eventMacroStep:
+		root.macroStep(ctx, ev)
+	case *eventJobDone:
+		root.jobDone(ctx, ev)
+	case *eventPipe:
+		root.pipeCommand(ctx, ev.source, ev.value)
+	case *eventSort:
+		root.sortColumn(ctx, ev.value)
+	case *eventGoColumn:
+		root.goColumn(ev.value)
+	case *eventColumnPositions:
+		root.setColumnPositions(ev.value)
+	case *eventColumnExport:
+		root.exportColumnTo(ctx, ev.value)
+	case *eventColumnSearch:
+		if ev.searchType == filter {
+			root.columnFilter(ctx, ev.value)
+		} else {
+			root.columnSearch(ctx, ev.value)
+		}
+	case *eventModeRule:
+		root.contentModeRule(ctx, ev.m)
+	case *eventRemoteQuery:
+		ev.reply <- root.remoteState()
+	case *eventReloadConfig:
+		root.reloadConfig(ctx)
 
 	// tcell events
 	case *tcell.EventResize:
@@ -148,6 +191,7 @@ func (root *Root) everyUpdate(ctx context.Context) {
 	if root.General.FollowAll || root.Doc.FollowMode || root.Doc.FollowSection {
 		root.follow(ctx)
 	}
+	root.restoreReloadLine(ctx)
 
 	if !root.skipDraw && root.Doc.height > 0 {
 		root.draw(ctx)
@@ -164,6 +208,17 @@ func (root *Root) keyEvent(ctx context.Context, ev *tcell.EventKey) {
 	root.setMessage("")
 	switch root.input.Event.Mode() {
 	case Normal:
+		// Escape cancels the count prefix and key sequence instead of quitting.
+		if (root.countPending > 0 || len(root.keyPending) > 0) && ev.Key() == tcell.KeyEscape {
+			root.countPending = 0
+			root.resetKeyPending()
+			return
+		}
+		// Escape ends the visual mode instead of quitting.
+		if root.visual.active && ev.Key() == tcell.KeyEscape {
+			root.endVisual()
+			return
+		}
 		root.keyCapture(ev)
 	default:
 		root.inputEvent(ctx, ev)
@@ -245,7 +300,7 @@ type eventDocument struct {
 
 // SetDocument fires the eventDocument event.
 func (root *Root) SetDocument(docNum int) {
-	if docNum < 0 || docNum < root.DocumentLen() {
+	if docNum < 0 || docNum >= root.DocumentLen() {
 		return
 	}
 	root.sendDocument(docNum)
@@ -313,8 +368,9 @@ type eventReload struct {
 }
 
 // Reload fires the eventReload event.
+// The current document is determined in the event loop.
 func (root *Root) Reload(context.Context) {
-	root.sendReload(root.Doc)
+	root.sendReload(nil)
 }
 
 func (root *Root) sendReload(m *Document) {
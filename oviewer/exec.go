@@ -2,6 +2,7 @@ package oviewer
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -30,6 +31,8 @@ type Command struct {
 	docout *Document
 	docerr *Document
 	args   []string
+	// stdin is passed to the standard input of the command if it is not nil.
+	stdin []byte
 }
 
 // NewCommand return the structure of Command.
@@ -41,22 +44,31 @@ func NewCommand(args ...string) *Command {
 
 // Exec return the structure of oviewer.
 func (command *Command) Exec() (*Root, error) {
+	if err := command.start(); err != nil {
+		return nil, err
+	}
+	return NewOviewer(command.docout, command.docerr)
+}
+
+// start starts the command and reads stdout/stderr into the documents.
+func (command *Command) start() error {
 	docout, docerr, err := newOutErrDocument()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	command.docout = docout
 	command.docerr = docerr
 
-	//nolint:gosec
-	command.cmd = exec.Command(command.args[0], command.args[1:]...)
+	command.cmd = command.newCmd()
 	so, se, err := commandStart(command.cmd)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	command.stdout = so
 	command.stderr = se
 
+	command.docout.commandLine = strings.Join(command.args, " ")
+	command.docerr.commandLine = command.docout.commandLine
 	command.docout.Caption = "(" + command.cmd.Args[0] + ")" + command.docout.FileName
 	command.docerr.Caption = "(" + command.cmd.Args[0] + ")" + command.docerr.FileName
 	atomic.StoreInt32(&command.docout.closed, 0)
@@ -72,7 +84,17 @@ func (command *Command) Exec() (*Root, error) {
 	if err = command.docerr.ControlReader(se, command.stderrReload); err != nil {
 		log.Printf("%s", err)
 	}
-	return NewOviewer(command.docout, command.docerr)
+	return nil
+}
+
+// newCmd returns the exec.Cmd of the command.
+func (command *Command) newCmd() *exec.Cmd {
+	//nolint:gosec
+	cmd := exec.Command(command.args[0], command.args[1:]...)
+	if command.stdin != nil {
+		cmd.Stdin = bytes.NewReader(command.stdin)
+	}
+	return cmd
 }
 
 // Wait waits for the command to exit.
@@ -100,8 +122,7 @@ func (command *Command) Reload() *bufio.Reader {
 	} else {
 		command.docout.reset()
 	}
-	//nolint:gosec
-	command.cmd = exec.Command(command.args[0], command.args[1:]...)
+	command.cmd = command.newCmd()
 	so, se, err := commandStart(command.cmd)
 	if err != nil {
 		log.Println(err)
@@ -177,7 +198,7 @@ func newOutErrDocument() (*Document, *Document, error) {
 
 // commandStart starts the command.
 func commandStart(cmd *exec.Cmd) (io.Reader, io.Reader, error) {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
+	if cmd.Stdin == nil && !term.IsTerminal(int(os.Stdin.Fd())) {
 		cmd.Stdin = os.Stdin
 	}
 	return cmdOutput(cmd)
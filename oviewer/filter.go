@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 // filterDocument is a document for filtering.
@@ -13,17 +15,31 @@ type filterDocument struct {
 	w io.WriteCloser
 }
 
+// eventFilter represents a filter event.
+type eventFilter struct {
+	tcell.EventTime
+	str      string
+	nonMatch bool
+}
+
 // Filter fires the filter event.
+// The document is changed in the event loop.
 func (root *Root) Filter(str string, nonMatch bool) {
-	root.Doc.nonMatch = nonMatch
-	root.input.value = str
-	ev := &eventInputSearch{
-		searchType: filter,
-		value:      str,
+	ev := &eventFilter{
+		str:      str,
+		nonMatch: nonMatch,
 	}
+	ev.SetEventNow()
 	root.postEvent(ev)
 }
 
+// filterEvent filters the current document with nonMatch of the event.
+func (root *Root) filterEvent(ctx context.Context, ev *eventFilter) {
+	root.Doc.nonMatch = ev.nonMatch
+	root.input.value = ev.str
+	root.filter(ctx, ev.str)
+}
+
 // filter filters the document by the input value.
 func (root *Root) filter(ctx context.Context, str string) {
 	searcher := root.setSearcher(str, root.Config.CaseSensitive)
@@ -52,6 +68,7 @@ func (root *Root) filterDocument(ctx context.Context, searcher Searcher) {
 	render.general = mergeGeneral(m.general, render.general)
 
 	render.nonMatch = m.nonMatch
+	render.filterSearcher = searcher
 	render.Header = m.Header
 	render.SkipLines = m.SkipLines
 
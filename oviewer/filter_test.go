@@ -37,11 +37,33 @@ func TestRoot_Filter(t *testing.T) {
 			},
 			want: "test",
 		},
+		{
+			name: "FilterNonMatch",
+			fields: fields{
+				fileName: filepath.Join(testdata, "test.txt"),
+			},
+			args: args{
+				str:      "test",
+				nonMatch: true,
+			},
+			want: "test",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			root := rootFileReadHelper(t, tt.fields.fileName)
 			root.Filter(tt.args.str, tt.args.nonMatch)
+			// The document is not changed until the event is handled.
+			if root.input.value != "" {
+				t.Errorf("Filter() changed the input value %q", root.input.value)
+			}
+			ev, ok := root.Screen.PollEvent().(*eventFilter)
+			if !ok {
+				t.Fatal("Filter() did not post eventFilter")
+			}
+			if ev.str != tt.want || ev.nonMatch != tt.args.nonMatch {
+				t.Errorf("Filter() = %q %v, want %q %v", ev.str, ev.nonMatch, tt.want, tt.args.nonMatch)
+			}
 		})
 	}
 }
@@ -307,3 +329,18 @@ func TestRoot_closeAllFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestRoot_filterEvent(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "test.txt"))
+	root.filterEvent(context.Background(), &eventFilter{str: "test", nonMatch: true})
+	if root.DocumentLen() != 2 {
+		t.Fatalf("filterEvent() documents = %d, want 2", root.DocumentLen())
+	}
+	if !root.Doc.nonMatch {
+		t.Errorf("filterEvent() nonMatch = %v, want true", root.Doc.nonMatch)
+	}
+}
@@ -31,6 +31,16 @@ const (
 	JumpTarget                 // JumpTarget is the position to display the search results.
 	SaveBuffer                 // SaveBuffer is the save buffer.
 	SectionNum                 // SectionNum is the section number.
+	CommandLine                // CommandLine is the command line input mode.
+	MacroRecord                // MacroRecord is the name of the macro to record.
+	MacroPlay                  // MacroPlay is the name of the macro to play.
+	Pipe                       // Pipe is the shell command to pipe.
+	Sort                       // Sort is the sort order of the column.
+	ColumnSearch               // ColumnSearch is a search in the column.
+	ColumnFilter               // ColumnFilter is a filter by the column.
+	GoColumn                   // GoColumn is a column name input mode.
+	ColumnPositions            // ColumnPositions is the start positions of the columns.
+	ColumnExport               // ColumnExport is the target of the column export.
 )
 
 // Input represents the status of various inputs.
@@ -52,6 +62,14 @@ type Input struct {
 	MultiColorCandidate   *candidate
 	JumpTargetCandidate   *candidate
 	SaveBufferCandidate   *candidate
+	CommandCandidate      *candidate
+	MacroCandidate        *candidate
+	PipeCandidate         *candidate
+	SortCandidate         *candidate
+	ColumnSearchCandidate *candidate
+	GoColumnCandidate     *candidate
+	PositionsCandidate    *candidate
+	ExportCandidate       *candidate
 
 	value   string
 	cursorX int
@@ -73,6 +91,14 @@ func NewInput() *Input {
 	i.MultiColorCandidate = multiColorCandidate()
 	i.JumpTargetCandidate = jumpTargetCandidate()
 	i.SaveBufferCandidate = blankCandidate()
+	i.CommandCandidate = blankCandidate()
+	i.MacroCandidate = blankCandidate()
+	i.PipeCandidate = blankCandidate()
+	i.SortCandidate = sortCandidate()
+	i.ColumnSearchCandidate = blankCandidate()
+	i.GoColumnCandidate = blankCandidate()
+	i.PositionsCandidate = blankCandidate()
+	i.ExportCandidate = columnExportCandidate()
 
 	i.Event = &eventNormal{}
 	return &i
@@ -84,6 +110,9 @@ func (root *Root) inputEvent(ctx context.Context, ev *tcell.EventKey) {
 	// Not confirmed or canceled.
 	evKey := root.inputKeyConfig.Capture(ev)
 	if ok := root.input.keyEvent(evKey); !ok {
+		if root.input.Event.Mode() == Normal {
+			root.cancelMacroInput()
+		}
 		root.incrementalSearch(ctx)
 		return
 	}
@@ -95,6 +124,7 @@ func (root *Root) inputEvent(ctx context.Context, ev *tcell.EventKey) {
 
 	// Fires a confirmed event.
 	input := root.input
+	root.recordMacroInput(input.value)
 	nev := input.Event.Confirm(input.value)
 	root.postEvent(nev)
 	input.Event = normal()
@@ -161,6 +191,12 @@ func (input *Input) keyEvent(evKey *tcell.EventKey) bool {
 			input.cursorX = stringWidth(string(runes[:pos+1]))
 		}
 	case tcell.KeyTAB:
+		// The input mode with completion completes instead of inserting a tab.
+		if c, ok := input.Event.(Completer); ok {
+			input.value = c.Complete(input.value)
+			input.cursorX = stringWidth(input.value)
+			return false
+		}
 		pos := countToCursor(input.value, input.cursorX+1)
 		runes := []rune(input.value)
 		input.value = string(runes[:pos])
@@ -286,6 +322,12 @@ type Eventer interface {
 	Down(i string) string
 }
 
+// Completer is the interface implemented by input modes that complete with the tab key.
+type Completer interface {
+	// Complete returns the completed string.
+	Complete(i string) string
+}
+
 // candidate represents a input candidate list.
 type candidate struct {
 	mux  sync.Mutex
@@ -358,3 +400,43 @@ func blankCandidate() *candidate {
 		list: []string{},
 	}
 }
+
+// tabCompleter completes the input with the candidates returned by complete.
+type tabCompleter struct {
+	complete func(string) []string
+
+	// matches is the completion candidates of the last tab.
+	matches []string
+	// matchNum is the position of matches.
+	matchNum int
+	// completed is the string completed by the last tab.
+	completed string
+}
+
+// Complete returns the string completed when the tab key is pressed.
+// Pressing the tab key again cycles through the candidates.
+func (e *tabCompleter) Complete(str string) string {
+	if len(e.matches) > 1 && str == e.completed {
+		e.matchNum = (e.matchNum + 1) % len(e.matches)
+		e.completed = e.matches[e.matchNum]
+		return e.completed
+	}
+
+	e.matches = e.complete(str)
+	e.matchNum = 0
+	switch len(e.matches) {
+	case 0:
+		return str
+	case 1:
+		e.completed = e.matches[0]
+		return e.completed
+	}
+	prefix := commonPrefix(e.matches)
+	if len(prefix) > len(str) {
+		e.completed = prefix
+		e.matchNum = -1
+		return prefix
+	}
+	e.completed = e.matches[0]
+	return e.completed
+}
@@ -0,0 +1,55 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setCommandMode sets the inputMode to Command.
+func (root *Root) setCommandMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newCommandEvent(input.CommandCandidate, root.commandComplete)
+}
+
+// eventCommand represents the command input mode.
+type eventCommand struct {
+	tcell.EventTime
+	tabCompleter
+	clist *candidate
+	value string
+}
+
+// newCommandEvent returns commandEvent.
+func newCommandEvent(clist *candidate, complete func(string) []string) *eventCommand {
+	return &eventCommand{clist: clist, tabCompleter: tabCompleter{complete: complete}}
+}
+
+// Mode returns InputMode.
+func (*eventCommand) Mode() InputMode {
+	return CommandLine
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventCommand) Prompt() string {
+	return ":"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventCommand) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventCommand) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventCommand) Down(_ string) string {
+	return e.clist.down()
+}
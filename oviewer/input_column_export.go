@@ -0,0 +1,72 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnExportMode sets the inputMode to ColumnExport.
+func (root *Root) setColumnExportMode(context.Context) {
+	if !root.Doc.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	input := root.input
+	input.reset()
+	input.Event = newColumnExportEvent(input.ExportCandidate)
+}
+
+// columnExportCandidate returns the candidate to set to default.
+func columnExportCandidate() *candidate {
+	return &candidate{
+		list: []string{
+			"clipboard",
+			"document",
+			"save",
+			"clipboard marked",
+			"document marked",
+			"save marked",
+		},
+	}
+}
+
+// eventColumnExport represents the column export input mode.
+type eventColumnExport struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newColumnExportEvent returns columnExportEvent.
+func newColumnExportEvent(clist *candidate) *eventColumnExport {
+	return &eventColumnExport{clist: clist}
+}
+
+// Mode returns InputMode.
+func (e *eventColumnExport) Mode() InputMode {
+	return ColumnExport
+}
+
+// Prompt returns the prompt string in the input field.
+func (e *eventColumnExport) Prompt() string {
+	return "Column export:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnExport) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventColumnExport) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventColumnExport) Down(_ string) string {
+	return e.clist.down()
+}
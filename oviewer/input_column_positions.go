@@ -0,0 +1,66 @@
+package oviewer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnPositionsMode sets the inputMode to ColumnPositions.
+// The input starts with the current positions of the columns.
+func (root *Root) setColumnPositionsMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newColumnPositionsEvent(input.PositionsCandidate)
+	if root.Doc.ColumnWidth {
+		positions := widthPositions(root.Doc.columnWidths)
+		strs := make([]string, 0, len(positions))
+		for _, p := range positions {
+			strs = append(strs, strconv.Itoa(p))
+		}
+		input.value = strings.Join(strs, ",")
+		input.cursorX = len(input.value)
+	}
+}
+
+// eventColumnPositions represents the column positions input mode.
+type eventColumnPositions struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newColumnPositionsEvent returns columnPositionsEvent.
+func newColumnPositionsEvent(clist *candidate) *eventColumnPositions {
+	return &eventColumnPositions{clist: clist}
+}
+
+// Mode returns InputMode.
+func (e *eventColumnPositions) Mode() InputMode {
+	return ColumnPositions
+}
+
+// Prompt returns the prompt string in the input field.
+func (e *eventColumnPositions) Prompt() string {
+	return "Column positions:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnPositions) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventColumnPositions) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventColumnPositions) Down(_ string) string {
+	return e.clist.down()
+}
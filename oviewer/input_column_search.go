@@ -0,0 +1,83 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setColumnSearchMode sets the inputMode to ColumnSearch.
+func (root *Root) setColumnSearchMode(context.Context) {
+	root.setColumnSearchInput(forward)
+}
+
+// setColumnFilterMode sets the inputMode to ColumnFilter.
+func (root *Root) setColumnFilterMode(context.Context) {
+	root.setColumnSearchInput(filter)
+}
+
+// setColumnSearchInput sets the input mode of the column search.
+func (root *Root) setColumnSearchInput(searchType searchType) {
+	input := root.input
+	input.reset()
+	input.Event = newColumnSearchEvent(input.ColumnSearchCandidate, searchType, root.columnNameComplete)
+	root.OriginPos = root.Doc.topLN
+}
+
+// columnNameComplete returns the column names of the header that start with str.
+// The column condition is not completed.
+func (root *Root) columnNameComplete(str string) []string {
+	return prefixMatch(root.Doc.columnNames(), str, "", "")
+}
+
+// eventColumnSearch represents the column search input mode.
+type eventColumnSearch struct {
+	tcell.EventTime
+	tabCompleter
+	clist      *candidate
+	value      string
+	searchType searchType
+}
+
+// newColumnSearchEvent returns columnSearchEvent.
+func newColumnSearchEvent(clist *candidate, searchType searchType, complete func(string) []string) *eventColumnSearch {
+	return &eventColumnSearch{
+		clist:        clist,
+		searchType:   searchType,
+		tabCompleter: tabCompleter{complete: complete},
+	}
+}
+
+// Mode returns InputMode.
+func (e *eventColumnSearch) Mode() InputMode {
+	if e.searchType == filter {
+		return ColumnFilter
+	}
+	return ColumnSearch
+}
+
+// Prompt returns the prompt string in the input field.
+func (e *eventColumnSearch) Prompt() string {
+	if e.searchType == filter {
+		return "Column filter:"
+	}
+	return "Column search:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventColumnSearch) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventColumnSearch) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventColumnSearch) Down(_ string) string {
+	return e.clist.down()
+}
@@ -0,0 +1,58 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setGoColumnMode sets the inputMode to GoColumn.
+func (root *Root) setGoColumnMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newGoColumnEvent(input.GoColumnCandidate, root.columnNameComplete)
+}
+
+// eventGoColumn represents the column name input mode.
+type eventGoColumn struct {
+	tcell.EventTime
+	tabCompleter
+	clist *candidate
+	value string
+}
+
+// newGoColumnEvent returns goColumnEvent.
+func newGoColumnEvent(clist *candidate, complete func(string) []string) *eventGoColumn {
+	return &eventGoColumn{
+		clist:        clist,
+		tabCompleter: tabCompleter{complete: complete},
+	}
+}
+
+// Mode returns InputMode.
+func (e *eventGoColumn) Mode() InputMode {
+	return GoColumn
+}
+
+// Prompt returns the prompt string in the input field.
+func (e *eventGoColumn) Prompt() string {
+	return "Go to column:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventGoColumn) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventGoColumn) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventGoColumn) Down(_ string) string {
+	return e.clist.down()
+}
@@ -0,0 +1,64 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setMacroPlayMode sets the inputMode to MacroPlay.
+// If the input is empty, the last macro is replayed.
+func (root *Root) setMacroPlayMode(context.Context) {
+	if len(root.Config.Macro) == 0 {
+		root.setMessage("No macro")
+		return
+	}
+	input := root.input
+	input.reset()
+	// The macros in the config are added before the played macros.
+	for _, name := range root.macroNames() {
+		if !contains(input.MacroCandidate.list, name) {
+			input.MacroCandidate.toAddTop(name)
+		}
+	}
+	input.Event = newMacroPlayEvent(input.MacroCandidate)
+}
+
+// eventMacroPlay represents the macro play input mode.
+type eventMacroPlay struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newMacroPlayEvent returns macroPlayEvent.
+func newMacroPlayEvent(clist *candidate) *eventMacroPlay {
+	return &eventMacroPlay{clist: clist}
+}
+
+// Mode returns InputMode.
+func (*eventMacroPlay) Mode() InputMode {
+	return MacroPlay
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventMacroPlay) Prompt() string {
+	return "Play macro:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventMacroPlay) Confirm(str string) tcell.Event {
+	e.value = str
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventMacroPlay) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventMacroPlay) Down(_ string) string {
+	return e.clist.down()
+}
@@ -0,0 +1,53 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setMacroRecordMode sets the inputMode to MacroRecord.
+func (root *Root) setMacroRecordMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newMacroRecordEvent(input.MacroCandidate)
+}
+
+// eventMacroRecord represents the macro record input mode.
+type eventMacroRecord struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newMacroRecordEvent returns macroRecordEvent.
+func newMacroRecordEvent(clist *candidate) *eventMacroRecord {
+	return &eventMacroRecord{clist: clist}
+}
+
+// Mode returns InputMode.
+func (*eventMacroRecord) Mode() InputMode {
+	return MacroRecord
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventMacroRecord) Prompt() string {
+	return "Record macro:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventMacroRecord) Confirm(str string) tcell.Event {
+	e.value = str
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventMacroRecord) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventMacroRecord) Down(_ string) string {
+	return e.clist.down()
+}
@@ -0,0 +1,62 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setPipeMode sets the inputMode to Pipe.
+func (root *Root) setPipeMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newPipeEvent(input.PipeCandidate, root.defaultPipeSource())
+}
+
+// eventPipe represents the pipe input mode.
+type eventPipe struct {
+	tcell.EventTime
+	clist *candidate
+	// source is the input of the command (all, screen, selection or marked).
+	source string
+	value  string
+}
+
+// newPipeEvent returns pipeEvent.
+func newPipeEvent(clist *candidate, source string) *eventPipe {
+	return &eventPipe{clist: clist, source: source}
+}
+
+// Mode returns InputMode.
+func (*eventPipe) Mode() InputMode {
+	return Pipe
+}
+
+// Prompt returns the prompt string in the input field.
+func (e *eventPipe) Prompt() string {
+	return "(" + e.source + ")|"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventPipe) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventPipe) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventPipe) Down(_ string) string {
+	return e.clist.down()
+}
+
+// Complete switches the input of the command when the tab key is pressed.
+func (e *eventPipe) Complete(str string) string {
+	e.source = nextPipeSource(e.source)
+	return str
+}
@@ -7,8 +7,9 @@ import (
 )
 
 // setSaveBuffer is a wrapper to move to setSaveBufferMode.
+// The visual selection can be saved even for regular files.
 func (root *Root) setSaveBuffer(ctx context.Context) {
-	if root.Doc.seekable {
+	if root.Doc.seekable && !root.visual.active {
 		root.setMessage("Does not support saving regular files")
 		return
 	}
@@ -22,11 +23,22 @@ func (root *Root) setSaveBufferMode(context.Context) {
 	input.Event = newSaveBufferEvent(input.SaveBufferCandidate)
 }
 
+// setSaveStringMode sets the inputMode to SaveBuffer to save the string instead of the buffer.
+func (root *Root) setSaveStringMode(str string) {
+	input := root.input
+	input.reset()
+	event := newSaveBufferEvent(input.SaveBufferCandidate)
+	event.text = str
+	input.Event = event
+}
+
 // eventSaveBuffer represents the mode input mode.
 type eventSaveBuffer struct {
 	tcell.EventTime
 	clist *candidate
 	value string
+	// text is saved instead of the buffer if not empty.
+	text string
 }
 
 // newSaveBufferEvent returns SaveBufferModeEvent.
@@ -0,0 +1,79 @@
+package oviewer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// setSortMode sets the inputMode to Sort.
+func (root *Root) setSortMode(context.Context) {
+	input := root.input
+	input.reset()
+	input.Event = newSortEvent(input.SortCandidate)
+}
+
+// sortCandidate returns the candidate to set to default.
+func sortCandidate() *candidate {
+	return &candidate{
+		list: []string{
+			"lexical",
+			"-lexical",
+			"numeric",
+			"-numeric",
+			"size",
+			"-size",
+			"time",
+			"-time",
+		},
+	}
+}
+
+// eventSort represents the sort input mode.
+type eventSort struct {
+	tcell.EventTime
+	clist *candidate
+	value string
+}
+
+// newSortEvent returns sortEvent.
+func newSortEvent(clist *candidate) *eventSort {
+	return &eventSort{clist: clist}
+}
+
+// Mode returns InputMode.
+func (*eventSort) Mode() InputMode {
+	return Sort
+}
+
+// Prompt returns the prompt string in the input field.
+func (*eventSort) Prompt() string {
+	return "Sort column by:"
+}
+
+// Confirm returns the event when the input is confirmed.
+func (e *eventSort) Confirm(str string) tcell.Event {
+	e.value = str
+	e.clist.toLast(str)
+	e.SetEventNow()
+	return e
+}
+
+// Up returns strings when the up key is pressed during input.
+func (e *eventSort) Up(_ string) string {
+	return e.clist.up()
+}
+
+// Down returns strings when the down key is pressed during input.
+func (e *eventSort) Down(_ string) string {
+	return e.clist.down()
+}
+
+// Complete toggles the ascending and descending order when the tab key is pressed.
+func (*eventSort) Complete(str string) string {
+	if strings.HasPrefix(str, "-") {
+		return str[1:]
+	}
+	return "-" + str
+}
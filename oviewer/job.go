@@ -0,0 +1,62 @@
+package oviewer
+
+import (
+	"context"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// eventJobDone represents the end of the background job.
+type eventJobDone struct {
+	tcell.EventTime
+	// done is run in the event loop with the result of the job.
+	done func(context.Context)
+	err  error
+	name string
+	id   int
+}
+
+// runJob runs the job in the background, so that the screen can be operated while it runs.
+// The function returned by the job is run in the event loop when the job finishes.
+// The job is canceled with the cancel key, or when the next job starts.
+func (root *Root) runJob(ctx context.Context, name string, job func(context.Context) (func(context.Context), error)) {
+	if root.jobCancel != nil {
+		root.jobCancel()
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	root.jobCancel = cancel
+	root.jobID++
+	id := root.jobID
+	go func() {
+		done, err := job(jobCtx)
+		cancel()
+		ev := &eventJobDone{done: done, err: err, name: name, id: id}
+		ev.SetEventNow()
+		root.postEvent(ev)
+	}()
+}
+
+// jobDone runs the result of the job in the event loop.
+// The error of the job replaced by the next job is not shown.
+func (root *Root) jobDone(ctx context.Context, ev *eventJobDone) {
+	if ev.id == root.jobID {
+		root.jobCancel = nil
+	} else if ev.err != nil {
+		return
+	}
+	if ev.err != nil {
+		root.setMessageLogf("%s:%s", ev.name, ev.err)
+		return
+	}
+	ev.done(ctx)
+}
+
+// cancelJob cancels the running job.
+func (root *Root) cancelJob() {
+	if root.jobCancel == nil {
+		return
+	}
+	root.jobCancel()
+	root.jobCancel = nil
+	root.setMessage("cancel")
+}
@@ -0,0 +1,78 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// jobHelper waits for the background job to finish and runs its result.
+func jobHelper(t *testing.T, root *Root) {
+	t.Helper()
+	ch := make(chan *eventJobDone)
+	go func() {
+		for {
+			if ev, ok := root.Screen.PollEvent().(*eventJobDone); ok {
+				ch <- ev
+				return
+			}
+		}
+	}()
+	select {
+	case ev := <-ch:
+		root.jobDone(context.Background(), ev)
+	case <-time.After(time.Second):
+		t.Fatal("job timeout")
+	}
+}
+
+func TestRoot_runJob(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootHelper(t)
+	ctx := context.Background()
+
+	done := false
+	root.runJob(ctx, "test", func(context.Context) (func(context.Context), error) {
+		return func(context.Context) {
+			done = true
+		}, nil
+	})
+	jobHelper(t, root)
+	if !done || root.jobCancel != nil {
+		t.Errorf("runJob() done = %v, jobCancel = %v", done, root.jobCancel != nil)
+	}
+
+	// The job is canceled with the cancel key.
+	root.runJob(ctx, "test", func(ctx context.Context) (func(context.Context), error) {
+		<-ctx.Done()
+		return nil, ErrCancel
+	})
+	root.Cancel(ctx)
+	jobHelper(t, root)
+	if root.message != "test:cancel" {
+		t.Errorf("runJob() message = %q, want %q", root.message, "test:cancel")
+	}
+}
+
+func TestRoot_jobDoneStale(t *testing.T) {
+	root := rootHelper(t)
+	ctx := context.Background()
+	root.jobID = 2
+	root.setMessage("running")
+	root.jobDone(ctx, &eventJobDone{err: errors.New("stale"), name: "test", id: 1})
+	if root.message != "running" {
+		t.Errorf("jobDone() message = %q, want %q", root.message, "running")
+	}
+	// The result of the replaced job is still opened.
+	done := false
+	root.jobDone(ctx, &eventJobDone{done: func(context.Context) { done = true }, id: 1})
+	if !done {
+		t.Errorf("jobDone() did not run the result")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"strings"
 
 	"code.rocketnine.space/tslocum/cbind"
@@ -56,6 +57,8 @@ const (
 	actionRemoveAllMark  = "remove_all_mark"
 	actionMoveMark       = "next_mark"
 	actionMovePrevMark   = "previous_mark"
+	actionLetterMark     = "letter_mark"
+	actionGoLetterMark   = "goto_letter_mark"
 	actionViewMode       = "set_view_mode"
 	actionAlternate      = "alter_rows_mode"
 	actionLineNumMode    = "line_number_mode"
@@ -64,6 +67,22 @@ const (
 	actionWrap           = "wrap_mode"
 	actionColumnMode     = "column_mode"
 	actionColumnWidth    = "column_width"
+	actionColumnAlign    = "column_align"
+	actionSortColumn     = "sort_column"
+	actionColumnHide     = "column_hide"
+	actionColumnShow     = "column_show"
+	actionColumnLeft     = "column_left"
+	actionColumnRight    = "column_right"
+	actionColumnFreeze   = "column_freeze"
+	actionColumnStats    = "column_stats"
+	actionColumnSearch   = "column_search"
+	actionColumnFilter   = "column_filter"
+	actionRecordView     = "record_view"
+	actionGoColumn       = "goto_column"
+	actionPositions      = "column_positions"
+	actionBoundaryLeft   = "column_boundary_left"
+	actionBoundaryRight  = "column_boundary_right"
+	actionColumnExport   = "column_export"
 	actionBackSearch     = "backsearch"
 	actionDelimiter      = "delimiter"
 	actionHeader         = "header"
@@ -71,6 +90,9 @@ const (
 	actionTabWidth       = "tabwidth"
 	actionGoLine         = "goto"
 	actionSectionNum     = "section_header_num"
+	actionCommand        = "command"
+	actionMacroRecord    = "macro_record"
+	actionMacroPlay      = "macro_play"
 	actionNextSearch     = "next_search"
 	actionNextBackSearch = "next_backsearch"
 	actionNextDoc        = "next_doc"
@@ -81,7 +103,12 @@ const (
 	actionMultiColor     = "multi_color"
 	actionJumpTarget     = "jump_target"
 	actionSaveBuffer     = "save_buffer"
+	actionEditor         = "editor"
+	actionPipe           = "pipe"
 	actionHideOther      = "hide_other"
+	actionVisualLine     = "visual_line"
+	actionVisualBlock    = "visual_block"
+	actionVisualCopy     = "visual_copy"
 
 	inputCaseSensitive      = "input_casesensitive"
 	inputSmartCaseSensitive = "input_smart_casesensitive"
@@ -137,10 +164,28 @@ func (root *Root) handlers() map[string]func(context.Context) {
 		actionLastSection:    root.lastSection,
 		actionMoveMark:       root.markNext,
 		actionMovePrevMark:   root.markPrev,
+		actionLetterMark:     root.letterMark,
+		actionGoLetterMark:   root.goLetterMark,
 		actionViewMode:       root.setViewInputMode,
 		actionWrap:           root.toggleWrapMode,
 		actionColumnMode:     root.toggleColumnMode,
 		actionColumnWidth:    root.toggleColumnWidth,
+		actionColumnAlign:    root.toggleColumnAlign,
+		actionSortColumn:     root.setSortMode,
+		actionColumnHide:     root.hideColumn,
+		actionColumnShow:     root.showColumns,
+		actionColumnLeft:     root.moveColumnLayoutLeft,
+		actionColumnRight:    root.moveColumnLayoutRight,
+		actionColumnFreeze:   root.toggleColumnFreeze,
+		actionColumnStats:    root.showColumnStats,
+		actionColumnSearch:   root.setColumnSearchMode,
+		actionColumnFilter:   root.setColumnFilterMode,
+		actionRecordView:     root.toggleRecordView,
+		actionGoColumn:       root.setGoColumnMode,
+		actionPositions:      root.setColumnPositionsMode,
+		actionBoundaryLeft:   root.moveColumnBoundaryLeft,
+		actionBoundaryRight:  root.moveColumnBoundaryRight,
+		actionColumnExport:   root.setColumnExportMode,
 		actionAlternate:      root.toggleAlternateRows,
 		actionLineNumMode:    root.toggleLineNumMode,
 		actionMark:           root.addMark,
@@ -155,6 +200,9 @@ func (root *Root) handlers() map[string]func(context.Context) {
 		actionTabWidth:       root.setTabWidthMode,
 		actionGoLine:         root.setGoLineMode,
 		actionSectionNum:     root.setSectionNumMode,
+		actionCommand:        root.setCommandMode,
+		actionMacroRecord:    root.toggleMacroRecord,
+		actionMacroPlay:      root.setMacroPlayMode,
 		actionNextSearch:     root.sendNextSearch,
 		actionNextBackSearch: root.sendNextBackSearch,
 		actionNextDoc:        root.nextDoc,
@@ -165,7 +213,12 @@ func (root *Root) handlers() map[string]func(context.Context) {
 		actionMultiColor:     root.setMultiColorMode,
 		actionJumpTarget:     root.setJumpTargetMode,
 		actionSaveBuffer:     root.setSaveBuffer,
+		actionEditor:         root.openEditor,
+		actionPipe:           root.setPipeMode,
 		actionHideOther:      root.toggleHideOtherSection,
+		actionVisualLine:     root.toggleVisualLine,
+		actionVisualBlock:    root.toggleVisualBlock,
+		actionVisualCopy:     root.visualCopy,
 
 		inputCaseSensitive:      root.inputCaseSensitive,
 		inputSmartCaseSensitive: root.inputSmartCaseSensitive,
@@ -224,10 +277,28 @@ func defaultKeyBinds() KeyBind {
 		actionLastSection:    {"9"},
 		actionMoveMark:       {">"},
 		actionMovePrevMark:   {"<"},
+		actionLetterMark:     {"alt+m <letter>"},
+		actionGoLetterMark:   {"` <letter>"},
 		actionViewMode:       {"p", "P"},
 		actionWrap:           {"w", "W"},
 		actionColumnMode:     {"c"},
 		actionColumnWidth:    {"alt+o"},
+		actionColumnAlign:    {"alt+t"},
+		actionSortColumn:     {"O"},
+		actionColumnHide:     {"alt+h"},
+		actionColumnShow:     {"alt+u"},
+		actionColumnLeft:     {"alt+left"},
+		actionColumnRight:    {"alt+right"},
+		actionColumnFreeze:   {"alt+f"},
+		actionColumnStats:    {"alt+a"},
+		actionColumnSearch:   {"alt+/"},
+		actionColumnFilter:   {"alt+&"},
+		actionRecordView:     {"alt+x"},
+		actionGoColumn:       {"alt+g"},
+		actionPositions:      {"alt+w"},
+		actionBoundaryLeft:   {"alt+<"},
+		actionBoundaryRight:  {"alt+>"},
+		actionColumnExport:   {"alt+e"},
 		actionAlternate:      {"C"},
 		actionLineNumMode:    {"G"},
 		actionMark:           {"m"},
@@ -242,6 +313,9 @@ func defaultKeyBinds() KeyBind {
 		actionTabWidth:       {"t"},
 		actionGoLine:         {"g"},
 		actionSectionNum:     {"F7"},
+		actionCommand:        {":"},
+		actionMacroRecord:    {"alt+q"},
+		actionMacroPlay:      {"@"},
 		actionNextSearch:     {"n"},
 		actionNextBackSearch: {"N"},
 		actionNextDoc:        {"]"},
@@ -253,7 +327,12 @@ func defaultKeyBinds() KeyBind {
 		actionMultiColor:     {"."},
 		actionJumpTarget:     {"j"},
 		actionSaveBuffer:     {"S"},
+		actionEditor:         {"v"},
+		actionPipe:           {"|"},
 		actionHideOther:      {"alt+-"},
+		actionVisualLine:     {"V"},
+		actionVisualBlock:    {"alt+v"},
+		actionVisualCopy:     {"y"},
 
 		inputCaseSensitive:      {"alt+c"},
 		inputSmartCaseSensitive: {"alt+s"},
@@ -283,6 +362,8 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionFollowAll, "follow all mode toggle")
 	k.writeKeyBind(&b, actionToggleMouse, "enable/disable mouse")
 	k.writeKeyBind(&b, actionSaveBuffer, "save buffer to file")
+	k.writeKeyBind(&b, actionEditor, "open the file in the editor")
+	k.writeKeyBind(&b, actionPipe, "pipe to the shell command")
 
 	writeHeader(&b, "Moving")
 	k.writeKeyBind(&b, actionMoveDown, "forward by one line")
@@ -302,6 +383,7 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionMoveBeginLeft, "go to beginning of line")
 	k.writeKeyBind(&b, actionMoveEndRight, "go to end of line")
 	k.writeKeyBind(&b, actionGoLine, "go to line(input number or `.n` or `n%` allowed)")
+	k.writeKeyBind(&b, actionCommand, "command line (:set, :mode, :e, :w, :filter...)")
 
 	writeHeader(&b, "Move document")
 	k.writeKeyBind(&b, actionNextDoc, "next document")
@@ -315,6 +397,17 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionRemoveAllMark, "remove all mark")
 	k.writeKeyBind(&b, actionMoveMark, "move to next marked position")
 	k.writeKeyBind(&b, actionMovePrevMark, "move to previous marked position")
+	k.writeKeyBind(&b, actionLetterMark, "mark current position with a letter")
+	k.writeKeyBind(&b, actionGoLetterMark, "move to the position marked with a letter")
+
+	writeHeader(&b, "Visual selection")
+	k.writeKeyBind(&b, actionVisualLine, "line-wise visual mode toggle")
+	k.writeKeyBind(&b, actionVisualBlock, "block visual mode toggle")
+	k.writeKeyBind(&b, actionVisualCopy, "copy the visual selection to clipboard")
+
+	writeHeader(&b, "Macro")
+	k.writeKeyBind(&b, actionMacroRecord, "macro recording start/stop")
+	k.writeKeyBind(&b, actionMacroPlay, "macro play")
 
 	writeHeader(&b, "Search")
 	k.writeKeyBind(&b, actionSearch, "forward search mode")
@@ -327,6 +420,7 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionWrap, "wrap/nowrap toggle")
 	k.writeKeyBind(&b, actionColumnMode, "column mode toggle")
 	k.writeKeyBind(&b, actionColumnWidth, "column width toggle")
+	k.writeKeyBind(&b, actionColumnAlign, "aligned table toggle")
 	k.writeKeyBind(&b, actionRainbow, "column rainbow toggle")
 	k.writeKeyBind(&b, actionAlternate, "alternate rows of style toggle")
 	k.writeKeyBind(&b, actionLineNumMode, "line number toggle")
@@ -341,6 +435,23 @@ func (k KeyBind) String() string {
 	k.writeKeyBind(&b, actionMultiColor, "multi color highlight")
 	k.writeKeyBind(&b, actionJumpTarget, "jump target(`.n` or `n%` or `section` allowed)")
 
+	writeHeader(&b, "Column")
+	k.writeKeyBind(&b, actionSortColumn, "sort rows by the column")
+	k.writeKeyBind(&b, actionColumnHide, "hide the column")
+	k.writeKeyBind(&b, actionColumnShow, "show all columns in the original order")
+	k.writeKeyBind(&b, actionColumnLeft, "move the column to the left")
+	k.writeKeyBind(&b, actionColumnRight, "move the column to the right")
+	k.writeKeyBind(&b, actionColumnFreeze, "freeze the columns up to the cursor toggle")
+	k.writeKeyBind(&b, actionColumnStats, "statistics of the column")
+	k.writeKeyBind(&b, actionColumnSearch, "search in the column (`status>=500` allowed)")
+	k.writeKeyBind(&b, actionColumnFilter, "filter by the column (`host~^web` allowed)")
+	k.writeKeyBind(&b, actionRecordView, "record view of the current row toggle")
+	k.writeKeyBind(&b, actionGoColumn, "go to column(input header name or number)")
+	k.writeKeyBind(&b, actionPositions, "start positions of the fixed-width columns")
+	k.writeKeyBind(&b, actionBoundaryLeft, "move the right boundary of the column left")
+	k.writeKeyBind(&b, actionBoundaryRight, "move the right boundary of the column right")
+	k.writeKeyBind(&b, actionColumnExport, "copy, save or open the column of all rows")
+
 	writeHeader(&b, "Section")
 	k.writeKeyBind(&b, actionSection, "section delimiter regular expression")
 	k.writeKeyBind(&b, actionSectionStart, "section start position")
@@ -390,6 +501,16 @@ func GetKeyBinds(config Config) KeyBind {
 		keyBind = defaultKeyBinds()
 	}
 
+	// Overlay with the preset.
+	preset := strings.ToLower(config.KeyBindPreset)
+	if preset != "" && preset != "default" {
+		if presetKeyBinds, ok := keyBindPresets[preset]; ok {
+			keyBind = overlayKeyBind(keyBind, presetKeyBinds())
+		} else {
+			log.Printf("unknown keybind preset: %s", config.KeyBindPreset)
+		}
+	}
+
 	// Overwrite with config file.
 	for k, v := range config.Keybind {
 		keyBind[k] = v
@@ -402,13 +523,15 @@ func (root *Root) setHandlers(ctx context.Context, keyBind KeyBind) error {
 	c := root.keyConfig
 	in := root.inputKeyConfig
 
-	actionHandlers := root.handlers()
+	actionHandlers := root.actionHandlers()
+	keyBind = root.macroKeyBind(keyBind, actionHandlers)
 
 	for name, keys := range keyBind {
 		handler := actionHandlers[name]
 		if handler == nil {
 			return fmt.Errorf("%w for [%s] unknown action", ErrFailedKeyBind, name)
 		}
+		keys = singleKeys(keys)
 
 		if strings.HasPrefix(name, "input_") {
 			if err := setHandler(ctx, in, name, keys, handler); err != nil {
@@ -420,7 +543,18 @@ func (root *Root) setHandlers(ctx context.Context, keyBind KeyBind) error {
 			return err
 		}
 	}
-	return nil
+	return root.setKeySequences(ctx, keyBind, actionHandlers)
+}
+
+// singleKeys returns the keys excluding key sequences.
+func singleKeys(keys []string) []string {
+	singles := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !isKeySequence(k) {
+			singles = append(singles, k)
+		}
+	}
+	return singles
 }
 
 // setHandler sets multiple keys in one action handler.
@@ -460,7 +594,21 @@ func wrapEventHandler(ctx context.Context, f func(context.Context)) func(_ *tcel
 }
 
 // keyCapture does the actual key action.
+// The count prefix entered before the key is passed to the action.
 func (root *Root) keyCapture(ev *tcell.EventKey) bool {
-	root.keyConfig.Capture(ev)
+	if len(root.keyPending) == 0 && root.countKey(ev) {
+		return true
+	}
+	if root.keySequenceEvent(ev) {
+		return true
+	}
+	root.runKey(ev)
 	return true
 }
+
+// runKey runs the action of the key with the count prefix.
+func (root *Root) runKey(ev *tcell.EventKey) {
+	root.count, root.countPending = root.countPending, 0
+	defer func() { root.count = 0 }()
+	root.keyConfig.Capture(ev)
+}
@@ -0,0 +1,201 @@
+package oviewer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// keyBindPresets are the key bindings layered on top of the default key bindings.
+// The keys used in a preset are removed from the other actions.
+var keyBindPresets = map[string]func() KeyBind{
+	"less":  lessKeyBinds,
+	"vim":   vimKeyBinds,
+	"emacs": emacsKeyBinds,
+}
+
+// KeyBindPresets returns the names of the key binding presets.
+func KeyBindPresets() []string {
+	names := make([]string, 0, len(keyBindPresets)+1)
+	names = append(names, "default")
+	for name := range keyBindPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// lessKeyBinds is a preset close to less.
+// Only the keys of less are assigned, and ":" starts the file commands of less.
+func lessKeyBinds() KeyBind {
+	return map[string][]string{
+		actionExit:           {"q", "Q", ": q", ": Q", "Z Z"},
+		actionHelp:           {"h", "H"},
+		actionSync:           {"r", "ctrl+r", "ctrl+l"},
+		actionReload:         {"R"},
+		actionFollow:         {"F"},
+		actionMoveDown:       {"Enter", "Down", "e", "ctrl+e", "j", "ctrl+N"},
+		actionMoveUp:         {"Up", "y", "ctrl+y", "k", "ctrl+k", "ctrl+p"},
+		actionMoveTop:        {"Home", "g", "<", "alt+<"},
+		actionMoveBottom:     {"End", "G", ">", "alt+>"},
+		actionMovePgDn:       {"PageDown", "space", "f", "z", "ctrl+f", "ctrl+v", "alt+space"},
+		actionMovePgUp:       {"PageUp", "b", "w", "ctrl+b", "alt+v"},
+		actionMoveHfDn:       {"d", "ctrl+d"},
+		actionMoveHfUp:       {"u", "ctrl+u"},
+		actionMoveHfLeft:     {"alt+("},
+		actionMoveHfRight:    {"alt+)"},
+		actionMoveBeginLeft:  {"alt+{"},
+		actionMoveEndRight:   {"alt+}"},
+		actionSearch:         {"/"},
+		actionBackSearch:     {"?"},
+		actionNextSearch:     {"n"},
+		actionNextBackSearch: {"N"},
+		actionFilter:         {"&"},
+		actionEditor:         {"v"},
+		actionLetterMark:     {"m <letter>"},
+		actionGoLetterMark:   {"' <letter>"},
+		actionNextDoc:        {": n"},
+		actionPreviousDoc:    {": p"},
+		actionCloseDoc:       {": d"},
+	}
+}
+
+// vimKeyBinds is a preset close to vim.
+func vimKeyBinds() KeyBind {
+	return map[string][]string{
+		actionExit:          {"Escape", "q", "Z Z"},
+		actionHelp:          {"F1", "ctrl+F1", "ctrl+alt+c"},
+		actionFollow:        {"F"},
+		actionMoveDown:      {"j", "Down", "Enter", "ctrl+N"},
+		actionMoveUp:        {"k", "Up", "ctrl+p"},
+		actionMoveLeft:      {"h", "left"},
+		actionMoveRight:     {"l", "right"},
+		actionMoveBeginLeft: {"0", "shift+Home"},
+		actionMoveEndRight:  {"$", "shift+End"},
+		actionMoveTop:       {"g g", "Home"},
+		actionMoveBottom:    {"G", "End"},
+		actionMovePgDn:      {"ctrl+f", "PageDown"},
+		actionMovePgUp:      {"ctrl+b", "PageUp"},
+		actionMoveHfDn:      {"ctrl+d"},
+		actionMoveHfUp:      {"ctrl+u"},
+		actionNextSection:   {"] ]", "space"},
+		actionPrevSection:   {"[ [", "^"},
+		actionMoveMark:      {"] '"},
+		actionMovePrevMark:  {"[ '"},
+		actionNextDoc:       {"] b"},
+		actionPreviousDoc:   {"[ b"},
+		actionLineNumMode:   {"alt+n"},
+		actionGoLine:        {"alt+g"},
+		actionJumpTarget:    {"alt+j"},
+		actionVisualLine:    {"V"},
+		actionVisualBlock:   {"ctrl+v", "alt+v"},
+		actionVisualCopy:    {"y"},
+	}
+}
+
+// emacsKeyBinds is a preset close to emacs.
+func emacsKeyBinds() KeyBind {
+	return map[string][]string{
+		actionExit:          {"Escape", "q", "ctrl+x ctrl+c"},
+		actionCancel:        {"ctrl+c", "ctrl+g"},
+		actionFollow:        {"F"},
+		actionFollowAll:     {"ctrl+alt+a"},
+		actionPlain:         {"alt+p"},
+		actionRainbow:       {"ctrl+x r"},
+		actionSkipLines:     {"ctrl+x s"},
+		actionMoveDown:      {"ctrl+n", "Down", "Enter"},
+		actionMoveUp:        {"ctrl+p", "Up"},
+		actionMoveLeft:      {"ctrl+b", "left"},
+		actionMoveRight:     {"ctrl+f", "right"},
+		actionMoveBeginLeft: {"ctrl+a", "shift+Home"},
+		actionMoveEndRight:  {"ctrl+e", "shift+End"},
+		actionMoveTop:       {"alt+<", "Home"},
+		actionMoveBottom:    {"alt+>", "End"},
+		actionMovePgDn:      {"ctrl+v", "PageDown"},
+		actionMovePgUp:      {"alt+v", "PageUp"},
+		actionSearch:        {"ctrl+s", "/"},
+		actionBackSearch:    {"ctrl+r", "?"},
+		actionGoLine:        {"alt+g g", "g"},
+		actionNextDoc:       {"ctrl+x right", "]"},
+		actionPreviousDoc:   {"ctrl+x left", "["},
+		actionCloseDoc:      {"ctrl+x k", "ctrl+k"},
+		actionVisualLine:    {"ctrl+space", "V"},
+		actionVisualBlock:   {"ctrl+x space"},
+		actionVisualCopy:    {"alt+w", "y"},
+	}
+}
+
+// overlayKeyBind sets the key bindings of overlay on top of base.
+// The keys used in overlay are removed from the other actions in base,
+// and so are the keys that start a key sequence of overlay.
+func overlayKeyBind(base KeyBind, overlay KeyBind) KeyBind {
+	for name, keys := range overlay {
+		removes := append(slices.Clone(keys), firstKeys(keys)...)
+		for other, otherKeys := range base {
+			if other == name || isInputAction(other) != isInputAction(name) {
+				continue
+			}
+			base[other] = removeKeys(otherKeys, removes)
+		}
+	}
+	for name, keys := range overlay {
+		base[name] = keys
+	}
+	return base
+}
+
+// firstKeys returns the first keys of the key sequences.
+func firstKeys(keys []string) []string {
+	var firsts []string
+	for _, k := range keys {
+		if isKeySequence(k) {
+			firsts = append(firsts, strings.Fields(k)[0])
+		}
+	}
+	return firsts
+}
+
+// isInputAction returns true if the action is for the input mode.
+func isInputAction(name string) bool {
+	return strings.HasPrefix(name, "input_")
+}
+
+// removeKeys returns keys excluding removes.
+func removeKeys(keys []string, removes []string) []string {
+	ret := make([]string, 0, len(keys))
+	for _, k := range keys {
+		found := false
+		for _, r := range removes {
+			if sameKey(k, r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, k)
+		}
+	}
+	return ret
+}
+
+// sameKey returns true if the key strings represent the same key or key sequence.
+func sameKey(a string, b string) bool {
+	fa, fb := strings.Fields(a), strings.Fields(b)
+	if len(fa) != len(fb) {
+		return false
+	}
+	for i := range fa {
+		sa, err := decodeKeyStroke(fa[i])
+		if err != nil {
+			return false
+		}
+		sb, err := decodeKeyStroke(fb[i])
+		if err != nil {
+			return false
+		}
+		if sa != sb {
+			return false
+		}
+	}
+	return true
+}
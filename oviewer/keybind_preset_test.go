@@ -0,0 +1,147 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestKeyBindPresets(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	for _, preset := range KeyBindPresets() {
+		t.Run(preset, func(t *testing.T) {
+			root := rootHelper(t)
+			root.Config.KeyBindPreset = preset
+			keyBind, err := root.setKeyConfig(context.Background())
+			if err != nil {
+				t.Fatalf("Root.setKeyConfig() error = %v", err)
+			}
+			// A key is assigned to only one action.
+			used := make(map[string]string)
+			for name, keys := range keyBind {
+				if isInputAction(name) {
+					continue
+				}
+				for _, k := range keys {
+					for u, action := range used {
+						if sameKey(k, u) {
+							t.Errorf("%s: [%s] is assigned to %s and %s", preset, k, action, name)
+						}
+					}
+					used[k] = name
+				}
+			}
+		})
+	}
+}
+
+func TestGetKeyBinds_preset(t *testing.T) {
+	config := NewConfig()
+	config.KeyBindPreset = "vim"
+	config.Keybind = map[string][]string{
+		actionMoveTop: {"Home"},
+	}
+	keyBind := GetKeyBinds(config)
+	if got, want := keyBind[actionMoveDown], []string{"j", "Down", "Enter", "ctrl+N"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyBinds() down = %v, want %v", got, want)
+	}
+	// The user's Keybind is set on top of the preset.
+	if got, want := keyBind[actionMoveTop], []string{"Home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyBinds() top = %v, want %v", got, want)
+	}
+	// The key used in the preset is removed from the other action.
+	if got, want := keyBind[actionJumpTarget], []string{"alt+j"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyBinds() jump_target = %v, want %v", got, want)
+	}
+}
+
+func Test_overlayKeyBind(t *testing.T) {
+	base := KeyBind{
+		actionMoveDown:   {"Down", "j"},
+		actionJumpTarget: {"j"},
+		inputNext:        {"Down"},
+	}
+	overlay := KeyBind{
+		actionMoveUp: {"Up", "j"},
+	}
+	want := KeyBind{
+		actionMoveDown:   {"Down"},
+		actionJumpTarget: {},
+		actionMoveUp:     {"Up", "j"},
+		inputNext:        {"Down"},
+	}
+	if got := overlayKeyBind(base, overlay); !reflect.DeepEqual(got, want) {
+		t.Errorf("overlayKeyBind() = %v, want %v", got, want)
+	}
+}
+
+func TestGetKeyBinds_presetLess(t *testing.T) {
+	config := NewConfig()
+	config.KeyBindPreset = "less"
+	keyBind := GetKeyBinds(config)
+	// space pages forward as in less.
+	if got := keyBind[actionMovePgDn]; !slices.Contains(got, "space") {
+		t.Errorf("GetKeyBinds() page_down = %v, want space", got)
+	}
+	if got := keyBind[actionNextSection]; slices.Contains(got, "space") {
+		t.Errorf("GetKeyBinds() next_section = %v, want without space", got)
+	}
+	// The file commands of less.
+	if got, want := keyBind[actionNextDoc], []string{": n"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyBinds() next_doc = %v, want %v", got, want)
+	}
+	if got, want := keyBind[actionCloseDoc], []string{": d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyBinds() close_doc = %v, want %v", got, want)
+	}
+	// ctrl+k moves up as in less.
+	if got := keyBind[actionMoveUp]; !slices.Contains(got, "ctrl+k") {
+		t.Errorf("GetKeyBinds() up = %v, want ctrl+k", got)
+	}
+	// The keys that start the sequences of less are removed from the other actions.
+	if got := keyBind[actionCommand]; slices.Contains(got, ":") {
+		t.Errorf("GetKeyBinds() command = %v, want without :", got)
+	}
+	if got := keyBind[actionMark]; slices.Contains(got, "m") {
+		t.Errorf("GetKeyBinds() mark = %v, want without m", got)
+	}
+}
+
+func TestRoot_letterMarkLess(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.Config.KeyBindPreset = "less"
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	root.draw(ctx)
+
+	root.Doc.topLN = 10
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'm', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone))
+	if got := root.Doc.letterMarks['a']; got != 10 {
+		t.Errorf("letterMarks[a] = %v, want %v", got, 10)
+	}
+	root.Doc.topLN = 0
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, '\'', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone))
+	if root.Doc.topLN != 10 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 10)
+	}
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, '\'', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone))
+	if root.message != "No mark 'b'" {
+		t.Errorf("message = %q, want %q", root.message, "No mark 'b'")
+	}
+}
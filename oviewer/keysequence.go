@@ -0,0 +1,308 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"code.rocketnine.space/tslocum/cbind"
+	"github.com/gdamore/tcell/v2"
+)
+
+// defaultKeySequenceTimeout is the default time to wait for the next key of a key sequence.
+const defaultKeySequenceTimeout = 1000 * time.Millisecond
+
+// letterKey is the key of a key sequence that matches any letter (e.g. "m <letter>").
+// The letter entered is passed to the action in keyLetter.
+const letterKey = "<letter>"
+
+// keyStroke is a decoded key.
+type keyStroke struct {
+	mod tcell.ModMask
+	key tcell.Key
+	ch  rune
+	// letter is true for letterKey.
+	letter bool
+}
+
+// keySequence is an action assigned to multiple keys (e.g. "g g", "] s", "Space f").
+type keySequence struct {
+	name    string
+	labels  []string
+	strokes []keyStroke
+	handler func()
+}
+
+// isKeySequence returns true if the key string is a key sequence.
+func isKeySequence(k string) bool {
+	return len(strings.Fields(k)) > 1
+}
+
+// decodeKeyStroke decodes a key string.
+func decodeKeyStroke(k string) (keyStroke, error) {
+	if strings.EqualFold(k, letterKey) {
+		return keyStroke{key: tcell.KeyRune, letter: true}, nil
+	}
+	mod, key, ch, err := cbind.Decode(k)
+	if err != nil {
+		return keyStroke{}, err
+	}
+	return keyStroke{mod: mod, key: key, ch: ch}, nil
+}
+
+// match returns true if the event matches the key.
+// Shift is ignored for runes because it is included in the rune.
+func (s keyStroke) match(ev *tcell.EventKey) bool {
+	if s.key != ev.Key() {
+		return false
+	}
+	if s.letter {
+		return unicode.IsLetter(ev.Rune()) && ev.Modifiers()&^tcell.ModShift == 0
+	}
+	if s.key == tcell.KeyRune {
+		return s.ch == ev.Rune() && s.mod&^tcell.ModShift == ev.Modifiers()&^tcell.ModShift
+	}
+	return s.mod == ev.Modifiers()
+}
+
+// overlap returns true if the keys match the same key.
+func (s keyStroke) overlap(other keyStroke) bool {
+	if s == other {
+		return true
+	}
+	if s.letter {
+		s, other = other, s
+	}
+	return other.letter && s.key == tcell.KeyRune && unicode.IsLetter(s.ch) && s.mod&^tcell.ModShift == 0
+}
+
+// newKeySequence returns a keySequence from the key string.
+func newKeySequence(name string, k string, handler func()) (keySequence, error) {
+	labels := strings.Fields(k)
+	strokes := make([]keyStroke, 0, len(labels))
+	for _, l := range labels {
+		s, err := decodeKeyStroke(l)
+		if err != nil {
+			return keySequence{}, fmt.Errorf("%w [%s] for %s: %w", ErrFailedKeyBind, k, name, err)
+		}
+		strokes = append(strokes, s)
+	}
+	return keySequence{name: name, labels: labels, strokes: strokes, handler: handler}, nil
+}
+
+// hasPrefix returns true if the sequence starts with the events.
+func (seq keySequence) hasPrefix(evs []*tcell.EventKey) bool {
+	if len(evs) > len(seq.strokes) {
+		return false
+	}
+	for i, ev := range evs {
+		if !seq.strokes[i].match(ev) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasStrokePrefix returns true if the sequence starts with the strokes of other.
+func (seq keySequence) hasStrokePrefix(other keySequence) bool {
+	if len(other.strokes) > len(seq.strokes) {
+		return false
+	}
+	for i, s := range other.strokes {
+		if !seq.strokes[i].overlap(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// letter returns the letter entered for letterKey of the sequence.
+func (seq keySequence) letter(evs []*tcell.EventKey) rune {
+	for i, s := range seq.strokes {
+		if s.letter && i < len(evs) {
+			return evs[i].Rune()
+		}
+	}
+	return 0
+}
+
+// setKeySequences sets the key sequences of the actions.
+func (root *Root) setKeySequences(ctx context.Context, keyBind KeyBind, actionHandlers map[string]func(context.Context)) error {
+	var seqs, singles []keySequence
+	for name, keys := range keyBind {
+		handler := actionHandlers[name]
+		if handler == nil {
+			continue
+		}
+		for _, k := range keys {
+			if !isKeySequence(k) {
+				if isInputAction(name) {
+					continue
+				}
+				if single, err := newKeySequence(name, k, nil); err == nil {
+					singles = append(singles, single)
+				}
+				continue
+			}
+			if isInputAction(name) {
+				return fmt.Errorf("%w [%s] for %s: key sequence cannot be used for input", ErrFailedKeyBind, k, name)
+			}
+			h := handler
+			seq, err := newKeySequence(name, k, func() { h(ctx) })
+			if err != nil {
+				return err
+			}
+			seqs = append(seqs, seq)
+		}
+	}
+	if err := keySequenceConflict(seqs, singles); err != nil {
+		return err
+	}
+	root.keySequences = seqs
+	return nil
+}
+
+// keySequenceConflict returns an error if a sequence is the same as
+// or the beginning of another sequence,
+// or if a single key in singles is the first key of a sequence (e.g. "g" and "g g").
+func keySequenceConflict(seqs []keySequence, singles []keySequence) error {
+	sort.SliceStable(seqs, func(i, j int) bool {
+		return seqs[i].name < seqs[j].name
+	})
+	for i, a := range seqs {
+		for j, b := range seqs {
+			if i == j {
+				continue
+			}
+			if a.hasStrokePrefix(b) {
+				return fmt.Errorf("%w [%s] for %s and [%s] for %s", ErrKeySequenceConflict,
+					strings.Join(a.labels, " "), a.name, strings.Join(b.labels, " "), b.name)
+			}
+		}
+	}
+	sort.SliceStable(singles, func(i, j int) bool {
+		return singles[i].name < singles[j].name
+	})
+	for _, a := range seqs {
+		for _, b := range singles {
+			if a.hasStrokePrefix(b) {
+				return fmt.Errorf("%w [%s] for %s and [%s] for %s", ErrKeySequenceConflict,
+					strings.Join(a.labels, " "), a.name, strings.Join(b.labels, " "), b.name)
+			}
+		}
+	}
+	return nil
+}
+
+// keySequenceEvent processes the key as part of a key sequence.
+// It returns true if the key was consumed.
+func (root *Root) keySequenceEvent(ev *tcell.EventKey) bool {
+	if len(root.keySequences) == 0 {
+		return false
+	}
+
+	pending := make([]*tcell.EventKey, 0, len(root.keyPending)+1)
+	pending = append(pending, root.keyPending...)
+	pending = append(pending, ev)
+	prefix := false
+	for _, seq := range root.keySequences {
+		if !seq.hasPrefix(pending) {
+			continue
+		}
+		if len(seq.strokes) == len(pending) {
+			root.resetKeyPending()
+			root.count, root.countPending = root.countPending, 0
+			root.keyLetter = seq.letter(pending)
+			defer func() { root.count, root.keyLetter = 0, 0 }()
+			seq.handler()
+			return true
+		}
+		prefix = true
+	}
+	if prefix {
+		root.keyPending = pending
+		root.startKeySequenceTimeout()
+		return true
+	}
+
+	if len(root.keyPending) == 0 {
+		return false
+	}
+	// The sequence was broken, so the pending keys are processed as single keys.
+	root.flushKeyPending()
+	return root.keySequenceEvent(ev)
+}
+
+// flushKeyPending processes the pending keys as single keys.
+func (root *Root) flushKeyPending() {
+	evs := root.keyPending
+	root.resetKeyPending()
+	for _, e := range evs {
+		root.runKey(e)
+	}
+}
+
+// resetKeyPending discards the pending keys.
+func (root *Root) resetKeyPending() {
+	root.keyPending = nil
+	root.keySequenceGen++
+}
+
+// startKeySequenceTimeout waits for the next key of the key sequence.
+// If it times out, the pending keys are processed as single keys.
+func (root *Root) startKeySequenceTimeout() {
+	root.keySequenceGen++
+	gen := root.keySequenceGen
+	timeout := defaultKeySequenceTimeout
+	if root.Config.KeySequenceTimeout > 0 {
+		timeout = time.Duration(root.Config.KeySequenceTimeout) * time.Millisecond
+	}
+	time.AfterFunc(timeout, func() {
+		root.sendKeySequenceTimeout(gen)
+	})
+}
+
+// eventKeySequenceTimeout represents a timeout of the key sequence.
+type eventKeySequenceTimeout struct {
+	tcell.EventTime
+	gen int
+}
+
+// sendKeySequenceTimeout fires the eventKeySequenceTimeout event.
+func (root *Root) sendKeySequenceTimeout(gen int) {
+	ev := &eventKeySequenceTimeout{gen: gen}
+	ev.SetEventNow()
+	root.postEvent(ev)
+}
+
+// keySequenceTimeout processes the pending keys if no key is entered after them.
+func (root *Root) keySequenceTimeout(gen int) {
+	if gen != root.keySequenceGen || len(root.keyPending) == 0 {
+		return
+	}
+	root.flushKeyPending()
+}
+
+// pendingKeyString returns the count and keys being entered.
+func (root *Root) pendingKeyString() string {
+	var b strings.Builder
+	if root.countPending > 0 {
+		b.WriteString(fmt.Sprint(root.countPending))
+	}
+	if len(root.keyPending) == 0 {
+		return b.String()
+	}
+	for _, seq := range root.keySequences {
+		if seq.hasPrefix(root.keyPending) {
+			if b.Len() > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(strings.Join(seq.labels[:len(root.keyPending)], " "))
+			break
+		}
+	}
+	return b.String()
+}
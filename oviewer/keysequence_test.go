@@ -0,0 +1,168 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_keySequenceConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    map[string]string
+		singles map[string]string
+		wantErr error
+	}{
+		{
+			name: "testNoConflict",
+			keys: map[string]string{
+				"top":    "g g",
+				"bottom": "g e",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "testSame",
+			keys: map[string]string{
+				"top":    "g g",
+				"bottom": "g g",
+			},
+			wantErr: ErrKeySequenceConflict,
+		},
+		{
+			name: "testPrefix",
+			keys: map[string]string{
+				"top":    "space f",
+				"bottom": "space f g",
+			},
+			wantErr: ErrKeySequenceConflict,
+		},
+		{
+			name: "testSingleKey",
+			keys: map[string]string{
+				"top": "g g",
+			},
+			singles: map[string]string{
+				"bottom": "g",
+			},
+			wantErr: ErrKeySequenceConflict,
+		},
+		{
+			name: "testSingleLetter",
+			keys: map[string]string{
+				"goto_letter_mark": "<letter> x",
+			},
+			singles: map[string]string{
+				"bottom": "G",
+			},
+			wantErr: ErrKeySequenceConflict,
+		},
+		{
+			name: "testSingleKeyNoConflict",
+			keys: map[string]string{
+				"top": "g g",
+			},
+			singles: map[string]string{
+				"bottom": "G",
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seqs []keySequence
+			for name, k := range tt.keys {
+				seq, err := newKeySequence(name, k, func() {})
+				if err != nil {
+					t.Fatal(err)
+				}
+				seqs = append(seqs, seq)
+			}
+			var singles []keySequence
+			for name, k := range tt.singles {
+				single, err := newKeySequence(name, k, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				singles = append(singles, single)
+			}
+			if err := keySequenceConflict(seqs, singles); !errors.Is(err, tt.wantErr) {
+				t.Errorf("keySequenceConflict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoot_keySequenceEvent(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.Config.Keybind = map[string][]string{
+		actionMoveBottom: {"End", "z e"},
+		actionMoveDown:   {"Down", "z s"},
+	}
+	ctx := context.Background()
+	if _, err := root.setKeyConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+	root.prepareScreen()
+	root.draw(ctx)
+
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'z', tcell.ModNone))
+	if len(root.keyPending) != 1 {
+		t.Fatalf("keyPending = %v, want 1", len(root.keyPending))
+	}
+	if got := root.pendingKeyString(); got != "z" {
+		t.Errorf("pendingKeyString() = %v, want %v", got, "z")
+	}
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+	if root.Doc.topLN != 1 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 1)
+	}
+	if len(root.keyPending) != 0 {
+		t.Errorf("keyPending = %v, want 0", len(root.keyPending))
+	}
+
+	// Count prefix.
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, '3', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'z', tcell.ModNone))
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+	if root.Doc.topLN != 4 {
+		t.Errorf("topLN = %v, want %v", root.Doc.topLN, 4)
+	}
+
+	// Timeout clears the pending keys.
+	root.keyCapture(tcell.NewEventKey(tcell.KeyRune, 'z', tcell.ModNone))
+	root.keySequenceTimeout(root.keySequenceGen)
+	if len(root.keyPending) != 0 {
+		t.Errorf("keyPending = %v, want 0", len(root.keyPending))
+	}
+}
+
+func TestRoot_setKeySequencesError(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootHelper(t)
+	root.Config.Keybind = map[string][]string{
+		actionMoveTop:    {"g g"},
+		actionMoveBottom: {"g g"},
+	}
+	if _, err := root.setKeyConfig(context.Background()); !errors.Is(err, ErrKeySequenceConflict) {
+		t.Errorf("Root.setKeyConfig() error = %v, want %v", err, ErrKeySequenceConflict)
+	}
+
+	// The single key of the default key bindings is the first key of the sequence.
+	root.Config.Keybind = map[string][]string{
+		actionMoveTop: {"] ]"},
+	}
+	if _, err := root.setKeyConfig(context.Background()); !errors.Is(err, ErrKeySequenceConflict) {
+		t.Errorf("Root.setKeyConfig() error = %v, want %v", err, ErrKeySequenceConflict)
+	}
+}
@@ -0,0 +1,466 @@
+package oviewer
+
+import (
+	"context"
+	"slices"
+)
+
+// columnLayout is the order of the columns on the screen.
+// The columns in order are displayed first, and the rest follow in the original order.
+// The columns are numbered from 0.
+type columnLayout struct {
+	order   []int
+	ordered map[int]bool
+	hidden  map[int]bool
+}
+
+// columnLayout returns the layout from ColumnOrder and ColumnHide (numbered from 1).
+func (m *Document) columnLayout() columnLayout {
+	layout := columnLayout{
+		ordered: make(map[int]bool),
+		hidden:  make(map[int]bool),
+	}
+	for _, n := range m.ColumnOrder {
+		c := n - 1
+		if c < 0 || layout.ordered[c] {
+			continue
+		}
+		layout.order = append(layout.order, c)
+		layout.ordered[c] = true
+	}
+	for _, n := range m.ColumnHide {
+		if n > 0 {
+			layout.hidden[n-1] = true
+		}
+	}
+	return layout
+}
+
+// isColumnLayout returns true if the columns are hidden or reordered.
+func (m *Document) isColumnLayout() bool {
+	return m.ColumnMode && (len(m.ColumnHide) > 0 || len(m.ColumnOrder) > 0)
+}
+
+// isLayoutLine returns true if the lines are laid out for the columns.
+func (m *Document) isLayoutLine() bool {
+	return m.isAlign() || m.isColumnLayout() || (m.ColumnMode && m.ColumnFreeze > 0)
+}
+
+// display returns the position of the column c on the screen.
+// It returns -1 if the column is hidden.
+func (l columnLayout) display(c int) int {
+	if l.hidden[c] {
+		return -1
+	}
+	d := 0
+	for _, o := range l.order {
+		if o == c {
+			return d
+		}
+		if !l.hidden[o] {
+			d++
+		}
+	}
+	for o := 0; o < c; o++ {
+		if !l.ordered[o] && !l.hidden[o] {
+			d++
+		}
+	}
+	return d
+}
+
+// column returns the column displayed at the position d on the screen.
+// A negative position (no column) is returned as it is.
+func (l columnLayout) column(d int) int {
+	if d < 0 {
+		return d
+	}
+	for _, c := range l.order {
+		if l.hidden[c] {
+			continue
+		}
+		if d == 0 {
+			return c
+		}
+		d--
+	}
+	for c := 0; ; c++ {
+		if l.ordered[c] || l.hidden[c] {
+			continue
+		}
+		if d == 0 {
+			return c
+		}
+		d--
+	}
+}
+
+// columns returns the displayed columns from start to end in the order on the screen.
+func (l columnLayout) columns(start int, end int) []int {
+	cols := make([]int, 0, end-start)
+	for _, c := range l.order {
+		if c >= start && c < end && !l.hidden[c] {
+			cols = append(cols, c)
+		}
+	}
+	for c := start; c < end; c++ {
+		if !l.ordered[c] && !l.hidden[c] {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// sequence returns the first n columns in the order on the screen, including the hidden columns.
+func (l columnLayout) sequence(n int) []int {
+	seq := make([]int, 0, n)
+	for _, c := range l.order {
+		if len(seq) == n {
+			return seq
+		}
+		seq = append(seq, c)
+	}
+	for c := 0; len(seq) < n; c++ {
+		if !l.ordered[c] {
+			seq = append(seq, c)
+		}
+	}
+	return seq
+}
+
+// cursorColumn returns the column under the cursor (numbered from 0).
+// The cursor is the position on the screen.
+func (m *Document) cursorColumn() int {
+	return m.columnLayout().column(m.columnCursor)
+}
+
+// layoutWidth returns the width of the column c in the layout.
+// It is the width of the aligned column, or the width of the column in the ColumnWidth mode.
+func (m *Document) layoutWidth(c int) int {
+	w := m.alignWidth(c)
+	if !m.ColumnWidth || c >= len(m.columnWidths) {
+		return w
+	}
+	start := 0
+	if c > 0 {
+		start = m.columnWidths[c-1] + 1
+	}
+	end := m.columnWidths[c]
+	if len(m.ColumnPositions) > 0 {
+		// The column includes the boundary.
+		end++
+	}
+	return max(w, end-start)
+}
+
+// viewColumnWidths returns the positions of the column boundaries
+// on the screen in the ColumnWidth mode.
+func (m *Document) viewColumnWidths() []int {
+	if !m.isColumnLayout() || len(m.columnWidths) == 0 {
+		return m.columnWidths
+	}
+	shown := m.columnLayout().columns(0, len(m.columnWidths)+1)
+	widths := make([]int, 0, len(shown))
+	x := 0
+	for i := 0; i < len(shown)-1; i++ {
+		x += m.layoutWidth(shown[i])
+		widths = append(widths, x)
+		// separator.
+		x++
+	}
+	return widths
+}
+
+// layoutLine returns the line laid out for the columns.
+// The columns are aligned, reordered and hidden.
+// The contents are rearranged, but the string is the original,
+// and the positions map the original bytes to the rearranged contents.
+// The bytes of the hidden columns have no width.
+func (m *Document) layoutLine(lN int, line LineC) LineC {
+	if !line.valid {
+		return line
+	}
+	ranges, column := m.columnRanges(lN, line)
+	if !m.isAlign() && !m.isColumnLayout() {
+		line.frozen = m.frozenX(line, ranges, column)
+		return line
+	}
+
+	layout := m.columnLayout()
+	align := m.isAlign()
+	// The columns of the fixed width are padded to the width.
+	fixed := align || m.ColumnWidth
+	sep := m.layoutSeparator(line, ranges)
+	ellipsis := StrToContents(alignEllipsis, 0)
+	limit := 0
+	if align {
+		limit = m.alignMaxWidth()
+	}
+
+	lc := make(contents, 0, len(line.lc)+len(ranges)*len(sep))
+	pos := make(widthPos, len(line.str)+1)
+	for b := range pos {
+		pos[b] = -1
+	}
+	shown := layout.columns(column, column+len(ranges))
+	columns := make([][2]int, 0, len(shown))
+	frozen := -1
+	// x is the start of the column d on the screen.
+	x, d := 0, 0
+	for i, c := range shown {
+		cd := layout.display(c)
+		if fixed {
+			for ; d < cd; d++ {
+				x += m.layoutWidth(layout.column(d)) + len(sep)
+			}
+		}
+		if i > 0 {
+			for len(lc) < x-len(sep) {
+				lc = append(lc, alignSpace)
+			}
+			lc = append(lc, sep...)
+		}
+		// The line that starts inside a quoted field starts at the column.
+		for len(lc) < x {
+			lc = append(lc, alignSpace)
+		}
+		if frozen < 0 && m.ColumnFreeze > 0 && cd >= m.ColumnFreeze {
+			frozen = len(lc)
+		}
+
+		r := ranges[c-column]
+		ks, ke := line.pos.x(r[0]), line.pos.x(r[1])
+		if align {
+			ks, ke = trimColumn(line.lc, ks, ke)
+		} else if m.ColumnWidth {
+			_, ke = trimColumn(line.lc, ks, ke)
+		}
+		te := ke
+		if limit > 0 && ke-ks > limit {
+			te = ks + limit - len(ellipsis)
+			// Do not split a wide character.
+			if te > ks && line.lc[te-1].width == 2 {
+				te--
+			}
+		}
+		start := len(lc)
+		lc = append(lc, line.lc[ks:te]...)
+		ellipsisX := len(lc)
+		if te < ke {
+			lc = append(lc, ellipsis...)
+		}
+		end := len(lc)
+		for b := r[0]; b < r[1]; b++ {
+			switch bx := line.pos.x(b); {
+			case bx < ks:
+				pos[b] = start
+			case bx < te:
+				pos[b] = start + bx - ks
+			case te < ke:
+				pos[b] = ellipsisX
+			default:
+				pos[b] = end
+			}
+		}
+		if fixed && i < len(shown)-1 {
+			for len(lc) < x+m.layoutWidth(c) {
+				lc = append(lc, alignSpace)
+			}
+		}
+		for len(columns) <= cd {
+			columns = append(columns, [2]int{start, start})
+		}
+		columns[cd] = [2]int{start, len(lc)}
+
+		// The delimiter after the column.
+		next := len(line.str)
+		if c-column+1 < len(ranges) {
+			next = ranges[c-column+1][0]
+		}
+		for b := r[1]; b < next; b++ {
+			pos[b] = len(lc)
+		}
+		if fixed {
+			x += m.layoutWidth(c) + len(sep)
+			d = cd + 1
+		}
+	}
+
+	// The hidden columns have no width.
+	prev := 0
+	for b := range pos {
+		if pos[b] < 0 {
+			pos[b] = prev
+		}
+		prev = pos[b]
+	}
+	pos[len(line.str)] = len(lc)
+
+	if frozen < 0 {
+		frozen = 0
+		if m.ColumnFreeze > 0 && len(ranges) > 1 {
+			frozen = len(lc)
+		}
+	}
+	line.lc = lc
+	line.pos = pos
+	line.columns = columns
+	line.frozen = frozen
+	return line
+}
+
+// layoutSeparator returns the separator between the columns in the layout.
+func (m *Document) layoutSeparator(line LineC, ranges [][2]int) contents {
+	if m.isAlign() {
+		sepStr := m.ColumnAlignSeparator
+		if sepStr == "" {
+			sepStr = alignDefaultSeparator
+		}
+		return StrToContents(sepStr, 0)
+	}
+	if m.ColumnWidth {
+		return contents{alignSpace}
+	}
+	// The first delimiter of the line.
+	if len(ranges) < 2 {
+		return nil
+	}
+	return line.lc[line.pos.x(ranges[0][1]):line.pos.x(ranges[1][0])]
+}
+
+// frozenX returns the width of the frozen columns of the line.
+// The line without columns is not frozen.
+func (m *Document) frozenX(line LineC, ranges [][2]int, column int) int {
+	i := m.ColumnFreeze - column
+	if m.ColumnFreeze <= 0 || i <= 0 || len(ranges) < 2 {
+		return 0
+	}
+	if i >= len(ranges) {
+		return len(line.lc)
+	}
+	return line.pos.x(ranges[i][0])
+}
+
+// frozenAdjustX is screenAdjustX for the screen with the frozen columns on the left.
+// The frozen columns are always displayed, and the rest is scrolled.
+func (m *Document) frozenAdjustX(frozen int, width int, cl int, cr int, widths []int, cursor int) (int, int, error) {
+	if frozen <= 0 {
+		return screenAdjustX(m.x, m.x+width, cl, cr, widths, cursor)
+	}
+	if cursor < m.ColumnFreeze {
+		return m.x, cursor, nil
+	}
+	x, cursor, err := screenAdjustX(m.x+frozen, m.x+width, cl, cr, widths, cursor)
+	return max(0, x-frozen), cursor, err
+}
+
+// layoutStarts returns the start positions of the displayed columns in the order on the screen.
+func (m *Document) layoutStarts(lN int, str string) []int {
+	fields, _ := m.columnFields(lN, str)
+	if len(fields) < 2 {
+		return nil
+	}
+	cols := m.columnLayout().columns(0, len(fields))
+	starts := make([]int, 0, len(cols))
+	for _, c := range cols {
+		starts = append(starts, fields[c][0])
+	}
+	return starts
+}
+
+// hideColumn hides the column of the cursor.
+func (root *Root) hideColumn(context.Context) {
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	last := m.rightmostColumn(root.scr)
+	if last <= 0 {
+		root.setMessage(ErrLastColumn.Error())
+		return
+	}
+	c := m.cursorColumn()
+	m.ColumnHide = append(slices.Clone(m.ColumnHide), c+1)
+	m.columnCursor = min(m.columnCursor, last-1)
+	m.resetAlign()
+	root.setMessagef("Hide column %d", c+1)
+}
+
+// showColumns shows all columns in the original order.
+func (root *Root) showColumns(context.Context) {
+	m := root.Doc
+	if m.isColumnLayout() {
+		m.columnCursor = m.cursorColumn()
+	}
+	m.ColumnHide = nil
+	m.ColumnOrder = nil
+	m.resetAlign()
+	root.setMessage("Show all columns")
+}
+
+// moveColumnLayoutLeft moves the column of the cursor to the left.
+func (root *Root) moveColumnLayoutLeft(context.Context) {
+	root.moveColumnLayout(-1)
+}
+
+// moveColumnLayoutRight moves the column of the cursor to the right.
+func (root *Root) moveColumnLayoutRight(context.Context) {
+	root.moveColumnLayout(1)
+}
+
+// moveColumnLayout swaps the column of the cursor with the next displayed column.
+// The cursor follows the column.
+func (root *Root) moveColumnLayout(n int) {
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	target := m.columnCursor + n
+	if target < 0 || target > m.rightmostColumn(root.scr) {
+		root.setMessage(ErrNoColumn.Error())
+		return
+	}
+	m.ColumnOrder = swapColumns(m.columnLayout(), m.columnCursor, target)
+	m.columnCursor = target
+	m.resetAlign()
+	root.setMessagef("Set ColumnOrder %v", m.ColumnOrder)
+}
+
+// swapColumns returns ColumnOrder (numbered from 1) where the columns displayed at d1 and d2 are swapped.
+func swapColumns(layout columnLayout, d1 int, d2 int) []int {
+	c1, c2 := layout.column(d1), layout.column(d2)
+	seq := layout.sequence(len(layout.order) + max(c1, c2) + 1)
+	// The columns after the swapped columns stay in the original order.
+	n := len(layout.order)
+	order := make([]int, 0, len(seq))
+	for i, c := range seq {
+		switch c {
+		case c1:
+			c = c2
+			n = max(n, i+1)
+		case c2:
+			c = c1
+			n = max(n, i+1)
+		}
+		order = append(order, c+1)
+	}
+	return order[:n]
+}
+
+// toggleColumnFreeze freezes the columns up to the cursor, or unfreezes the columns.
+func (root *Root) toggleColumnFreeze(context.Context) {
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	if m.ColumnFreeze == m.columnCursor+1 {
+		m.ColumnFreeze = 0
+	} else {
+		m.ColumnFreeze = m.columnCursor + 1
+		m.x = 0
+	}
+	root.setMessagef("Set ColumnFreeze %d", m.ColumnFreeze)
+}
@@ -0,0 +1,194 @@
+package oviewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_columnLayout(t *testing.T) {
+	m := docHelper(t, "a,b,c,d\n")
+	m.ColumnOrder = []int{3, 1}
+	m.ColumnHide = []int{2}
+	layout := m.columnLayout()
+	// c, a, d (b is hidden)
+	if got, want := layout.columns(0, 4), []int{2, 0, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columnLayout.columns() = %v, want %v", got, want)
+	}
+	for d, c := range []int{2, 0, 3} {
+		if got := layout.column(d); got != c {
+			t.Errorf("columnLayout.column(%d) = %v, want %v", d, got, c)
+		}
+		if got := layout.display(c); got != d {
+			t.Errorf("columnLayout.display(%d) = %v, want %v", c, got, d)
+		}
+	}
+	if got := layout.display(1); got != -1 {
+		t.Errorf("columnLayout.display(1) = %v, want -1", got)
+	}
+	if got := layout.column(-1); got != -1 {
+		t.Errorf("columnLayout.column(-1) = %v, want -1", got)
+	}
+	if got, want := layout.sequence(4), []int{2, 0, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("columnLayout.sequence() = %v, want %v", got, want)
+	}
+}
+
+func Test_swapColumns(t *testing.T) {
+	m := docHelper(t, "a,b,c,d\n")
+	m.ColumnHide = []int{2}
+	// a, c, d -> c, a, d
+	if got, want := swapColumns(m.columnLayout(), 0, 1), []int{3, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("swapColumns() = %v, want %v", got, want)
+	}
+	m.ColumnOrder = []int{3, 2, 1}
+	// c, a, d -> c, d, a
+	if got, want := swapColumns(m.columnLayout(), 1, 2), []int{3, 2, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("swapColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestDocument_layoutLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		str   string
+		align bool
+		order []int
+		hide  []int
+		lN    int
+		want  string
+	}{
+		{
+			name: "hide",
+			str:  "a,bb,c\n",
+			hide: []int{2},
+			want: "a,c",
+		},
+		{
+			name:  "order",
+			str:   "a,bb,c\n",
+			order: []int{3},
+			want:  "c,a,bb",
+		},
+		{
+			name:  "alignOrder",
+			str:   "name,size,type\nfoo,1,x\nlongname,12345,y\n",
+			align: true,
+			order: []int{3},
+			hide:  []int{2},
+			lN:    1,
+			want:  "x     foo",
+		},
+		{
+			name: "short",
+			str:  "a,b,c\nd\n",
+			hide: []int{1},
+			lN:   1,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := docHelper(t, tt.str)
+			m.setDelimiter(",")
+			m.ColumnMode = true
+			m.ColumnAlign = tt.align
+			m.ColumnOrder = tt.order
+			m.ColumnHide = tt.hide
+			m.prepareAlign()
+			line := m.getLineC(tt.lN, m.TabWidth)
+			got, _ := ContentsToStr(line.lc)
+			if got != tt.want {
+				t.Errorf("Document.layoutLine() = %q, want %q", got, tt.want)
+			}
+			org, _ := m.LineStr(tt.lN)
+			if line.str != org {
+				t.Errorf("Document.layoutLine() str = %q, want %q", line.str, org)
+			}
+			if line.pos.x(len(line.str)) != len(line.lc) {
+				t.Errorf("Document.layoutLine() pos end = %d, want %d", line.pos.x(len(line.str)), len(line.lc))
+			}
+		})
+	}
+}
+
+func TestDocument_layoutLineColumns(t *testing.T) {
+	m := docHelper(t, "a,bb,c\n")
+	m.setDelimiter(",")
+	m.ColumnMode = true
+	m.ColumnOrder = []int{3}
+	line := m.getLineC(0, m.TabWidth)
+	// "c,a,bb"
+	if got, want := line.columns, [][2]int{{0, 1}, {2, 3}, {4, 6}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.layoutLine() columns = %v, want %v", got, want)
+	}
+	// The second column "bb" is displayed at 4.
+	if got := line.pos.x(2); got != 4 {
+		t.Errorf("Document.layoutLine() x of bb = %d, want 4", got)
+	}
+	m.columnCursor = 0
+	if got := m.cursorColumn(); got != 2 {
+		t.Errorf("Document.cursorColumn() = %d, want 2", got)
+	}
+}
+
+func TestDocument_layoutLineWidth(t *testing.T) {
+	m := docHelper(t, "aa  bb  cc\n1   2   3\n")
+	m.ColumnMode = true
+	m.ColumnWidth = true
+	m.columnWidths = []int{2, 6}
+	m.ColumnHide = []int{2}
+	m.prepareAlign()
+	line := m.getLineC(1, m.TabWidth)
+	got, _ := ContentsToStr(line.lc)
+	// The leading spaces of the column are kept.
+	if want := "1   3"; got != want {
+		t.Errorf("Document.layoutLine() = %q, want %q", got, want)
+	}
+	if got, want := m.viewColumnWidths(), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.viewColumnWidths() = %v, want %v", got, want)
+	}
+}
+
+func TestDocument_moveColumnRightFreeze(t *testing.T) {
+	cols := []string{"id", strings.Repeat("a", 15), strings.Repeat("b", 15), strings.Repeat("c", 15), strings.Repeat("d", 15)}
+	m := docHelper(t, strings.Join(cols, ",")+"\n")
+	m.setDelimiter(",")
+	m.ColumnMode = true
+	m.ColumnFreeze = 1
+	m.width = 20
+	line := m.getLineC(0, m.TabWidth)
+	if line.frozen != 3 {
+		t.Fatalf("Document.layoutLine() frozen = %d, want 3", line.frozen)
+	}
+	scr := SCR{
+		lines: map[int]LineC{0: line},
+	}
+	starts := m.columnStarts(0, line.str)
+	for i := 0; i < 10 && m.columnCursor < len(cols)-1; i++ {
+		if err := m.moveColumnRight(1, scr, false); err != nil {
+			t.Fatal(err)
+		}
+		// The column of the cursor is displayed to the right of the frozen column.
+		cr := len(line.lc)
+		if m.columnCursor < len(starts)-1 {
+			cr = line.pos.x(starts[m.columnCursor+1])
+		}
+		if cr <= m.x+line.frozen {
+			t.Errorf("Document.moveColumnRight() column %d is hidden by the frozen column (x=%d)", m.columnCursor, m.x)
+		}
+	}
+	if m.columnCursor != len(cols)-1 {
+		t.Errorf("Document.moveColumnRight() cursor = %d, want %d", m.columnCursor, len(cols)-1)
+	}
+	if m.x == 0 {
+		t.Errorf("Document.moveColumnRight() did not scroll")
+	}
+	// The frozen column does not scroll.
+	if err := m.moveColumnLeft(len(cols)-1, scr, false); err != nil {
+		t.Fatal(err)
+	}
+	if m.columnCursor != 0 {
+		t.Errorf("Document.moveColumnLeft() cursor = %d, want 0", m.columnCursor)
+	}
+}
@@ -0,0 +1,246 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// MacroStep is one action of a macro.
+type MacroStep struct {
+	// Action is the name of the action (e.g. "delimiter", "filter", "next_mark").
+	Action string
+	// Input is the string entered in the prompt opened by the action.
+	Input string
+	// Count is the count prefix of the action.
+	Count int
+}
+
+// macroRecorder represents the state of the macro recording.
+type macroRecorder struct {
+	// name is the name of the macro being recorded.
+	name string
+	// steps is the recorded steps.
+	steps []MacroStep
+	// recording is true while recording.
+	recording bool
+	// awaitInput is true if the last step waits for the prompt input.
+	awaitInput bool
+}
+
+// isMacroAction returns true for actions that are not recorded in the macro.
+func isMacroAction(name string) bool {
+	return name == actionMacroRecord || name == actionMacroPlay
+}
+
+// actionHandlers returns the handlers of the actions wrapped for the macro recording.
+func (root *Root) actionHandlers() map[string]func(context.Context) {
+	handlers := root.handlers()
+	for name, handler := range handlers {
+		if isInputAction(name) || isMacroAction(name) {
+			continue
+		}
+		handlers[name] = root.recordHandler(name, handler)
+	}
+	return handlers
+}
+
+// recordHandler returns a handler that records the action while recording.
+func (root *Root) recordHandler(name string, handler func(context.Context)) func(context.Context) {
+	return func(ctx context.Context) {
+		count := root.count
+		handler(ctx)
+		if !root.macro.recording {
+			return
+		}
+		root.macro.steps = append(root.macro.steps, MacroStep{Action: name, Count: count})
+		root.macro.awaitInput = root.input.Event.Mode() != Normal
+	}
+}
+
+// recordMacroInput records the confirmed prompt input in the last step.
+func (root *Root) recordMacroInput(value string) {
+	if !root.macro.recording || !root.macro.awaitInput {
+		return
+	}
+	root.macro.steps[len(root.macro.steps)-1].Input = value
+	root.macro.awaitInput = false
+}
+
+// cancelMacroInput removes the last step whose prompt was canceled.
+func (root *Root) cancelMacroInput() {
+	if !root.macro.recording || !root.macro.awaitInput {
+		return
+	}
+	root.macro.steps = root.macro.steps[:len(root.macro.steps)-1]
+	root.macro.awaitInput = false
+}
+
+// toggleMacroRecord starts or stops the macro recording.
+func (root *Root) toggleMacroRecord(ctx context.Context) {
+	if root.macro.recording {
+		root.stopMacroRecord()
+		return
+	}
+	root.setMacroRecordMode(ctx)
+}
+
+// startMacroRecord starts recording the macro.
+func (root *Root) startMacroRecord(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		root.setMessage("macro: no name")
+		return
+	}
+	root.macro = macroRecorder{name: name, recording: true}
+	root.setMessagef("Recording macro %s", name)
+}
+
+// stopMacroRecord stops recording and stores the macro in the config.
+func (root *Root) stopMacroRecord() {
+	m := root.macro
+	root.macro = macroRecorder{}
+	if len(m.steps) == 0 {
+		root.setMessagef("Macro %s is empty", m.name)
+		return
+	}
+	if root.Config.Macro == nil {
+		root.Config.Macro = make(map[string][]MacroStep)
+	}
+	root.Config.Macro[m.name] = m.steps
+	root.input.MacroCandidate.toLast(m.name)
+	// Prompt for the file to save the macro in the format of the config file.
+	// The saved file can be included in the config file.
+	root.setSaveStringMode(macroYAML(m.name, m.steps))
+	root.setMessagef("Recorded macro %s (%d steps)", m.name, len(m.steps))
+}
+
+// macroYAML returns the macro in the format of the config file.
+func macroYAML(name string, steps []MacroStep) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Macro:\n  %s:\n", name)
+	for _, step := range steps {
+		fmt.Fprintf(&b, "    - Action: %s\n", step.Action)
+		if step.Input != "" {
+			fmt.Fprintf(&b, "      Input: %s\n", strconv.Quote(step.Input))
+		}
+		if step.Count > 0 {
+			fmt.Fprintf(&b, "      Count: %d\n", step.Count)
+		}
+	}
+	return b.String()
+}
+
+// macroKeyPrefix is the prefix of the action name that plays the macro.
+const macroKeyPrefix = "macro:"
+
+// macroKeyBind returns the key bindings with the keys of MacroKeybind,
+// and adds the handlers that play the macros to actionHandlers.
+func (root *Root) macroKeyBind(keyBind KeyBind, actionHandlers map[string]func(context.Context)) KeyBind {
+	if len(root.Config.MacroKeybind) == 0 {
+		return keyBind
+	}
+	bind := make(KeyBind, len(keyBind)+len(root.Config.MacroKeybind))
+	for name, keys := range keyBind {
+		bind[name] = keys
+	}
+	for name, keys := range root.Config.MacroKeybind {
+		macro := name
+		bind[macroKeyPrefix+name] = keys
+		actionHandlers[macroKeyPrefix+name] = func(context.Context) {
+			root.playMacro(macro)
+		}
+	}
+	return bind
+}
+
+// macroNames returns the names of the macros.
+func (root *Root) macroNames() []string {
+	names := make([]string, 0, len(root.Config.Macro))
+	for name := range root.Config.Macro {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunMacro fires the event that replays the macro.
+func (root *Root) RunMacro(name string) error {
+	steps, ok := root.Config.Macro[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMacroNotFound, name)
+	}
+	root.sendMacroStep(name, steps, 0)
+	return nil
+}
+
+// playMacro replays the macro.
+// If the name is empty, the last macro is replayed.
+func (root *Root) playMacro(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		list := root.input.MacroCandidate.list
+		if len(list) == 0 {
+			root.setMessage("macro: no name")
+			return
+		}
+		name = list[len(list)-1]
+	}
+	if root.macro.recording {
+		root.setMessage("macro: cannot play while recording")
+		return
+	}
+	if err := root.RunMacro(name); err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	root.input.MacroCandidate.toLast(name)
+}
+
+// eventMacroStep represents the replay of a macro step.
+type eventMacroStep struct {
+	tcell.EventTime
+	name  string
+	steps []MacroStep
+	num   int
+}
+
+// sendMacroStep fires the eventMacroStep event.
+func (root *Root) sendMacroStep(name string, steps []MacroStep, num int) {
+	ev := &eventMacroStep{name: name, steps: steps, num: num}
+	ev.SetEventNow()
+	root.postEvent(ev)
+}
+
+// macroStep runs one step of the macro.
+// The next step is fired as an event, so that it runs after the events fired by this step.
+func (root *Root) macroStep(ctx context.Context, ev *eventMacroStep) {
+	if ev.num >= len(ev.steps) {
+		root.setMessagef("Played macro %s", ev.name)
+		return
+	}
+	step := ev.steps[ev.num]
+	handler, ok := root.handlers()[step.Action]
+	if !ok || isInputAction(step.Action) || isMacroAction(step.Action) {
+		root.setMessageLogf("macro %s: invalid action %s", ev.name, step.Action)
+		return
+	}
+
+	root.count = step.Count
+	handler(ctx)
+	root.count = 0
+
+	// Confirm the prompt opened by the action with the recorded input.
+	input := root.input
+	if input.Event.Mode() != Normal {
+		input.value = step.Input
+		nev := input.Event.Confirm(step.Input)
+		input.Event = normal()
+		root.postEvent(nev)
+	}
+	root.sendMacroStep(ev.name, ev.steps, ev.num+1)
+}
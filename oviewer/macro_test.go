@@ -0,0 +1,117 @@
+package oviewer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_macroRecord(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.prepareScreen()
+	ctx := context.Background()
+	handlers := root.actionHandlers()
+
+	root.startMacroRecord("test")
+	handlers[actionDelimiter](ctx)
+	root.recordMacroInput(",")
+	root.input.Event = normal()
+	handlers[actionHeader](ctx)
+	root.cancelMacroInput()
+	root.input.Event = normal()
+	root.count = 2
+	handlers[actionMoveDown](ctx)
+	root.count = 0
+	root.toggleMacroRecord(ctx)
+
+	want := []MacroStep{
+		{Action: actionDelimiter, Input: ","},
+		{Action: actionMoveDown, Count: 2},
+	}
+	if got := root.Config.Macro["test"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Macro = %v, want %v", got, want)
+	}
+	if root.macro.recording {
+		t.Errorf("recording = %v, want %v", root.macro.recording, false)
+	}
+	// The save prompt is opened with the macro in the format of the config file.
+	ev, ok := root.input.Event.(*eventSaveBuffer)
+	if !ok {
+		t.Fatalf("input mode = %v, want %v", root.input.Event.Mode(), SaveBuffer)
+	}
+	if ev.text != macroYAML("test", want) {
+		t.Errorf("save text = %q, want %q", ev.text, macroYAML("test", want))
+	}
+}
+
+func Test_macroYAML(t *testing.T) {
+	steps := []MacroStep{
+		{Action: "delimiter", Input: ","},
+		{Action: "down", Count: 2},
+	}
+	want := "Macro:\n  test:\n    - Action: delimiter\n      Input: \",\"\n    - Action: down\n      Count: 2\n"
+	if got := macroYAML("test", steps); got != want {
+		t.Errorf("macroYAML() = %q, want %q", got, want)
+	}
+}
+
+func TestRoot_RunMacro(t *testing.T) {
+	root := rootHelper(t)
+	root.Config.Macro = map[string][]MacroStep{
+		"test": {{Action: "down"}},
+	}
+	if err := root.RunMacro("test"); err != nil {
+		t.Errorf("RunMacro() error = %v", err)
+	}
+	if err := root.RunMacro("unknown"); !errors.Is(err, ErrMacroNotFound) {
+		t.Errorf("RunMacro() error = %v, want %v", err, ErrMacroNotFound)
+	}
+}
+
+func TestRoot_macroKeyBind(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootHelper(t)
+	root.Config.Macro = map[string][]MacroStep{
+		"test": {{Action: "down"}},
+	}
+	root.Config.MacroKeybind = map[string][]string{
+		"test": {"F5", "z m"},
+	}
+	keyBind, err := root.setKeyConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keyBind[macroKeyPrefix+"test"]; ok {
+		t.Errorf("setKeyConfig() keyBind has %s", macroKeyPrefix+"test")
+	}
+	handlers := root.actionHandlers()
+	bind := root.macroKeyBind(keyBind, handlers)
+	if got := bind[macroKeyPrefix+"test"]; !reflect.DeepEqual(got, []string{"F5", "z m"}) {
+		t.Errorf("macroKeyBind() = %v, want %v", got, []string{"F5", "z m"})
+	}
+	handlers[macroKeyPrefix+"test"](context.Background())
+	list := root.input.MacroCandidate.list
+	if len(list) == 0 || list[len(list)-1] != "test" {
+		t.Errorf("MacroCandidate = %v, want test", list)
+	}
+	found := false
+	for _, seq := range root.keySequences {
+		if seq.name == macroKeyPrefix+"test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("keySequences does not have %s", macroKeyPrefix+"test")
+	}
+}
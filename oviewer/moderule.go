@@ -0,0 +1,190 @@
+package oviewer
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ModeRule selects the view mode of the document automatically.
+// The rule matches if any of the specified conditions match.
+type ModeRule struct {
+	// Mode is the name of the mode in Mode.
+	Mode string
+	// Pattern is the glob pattern of the file name (e.g. "*.csv").
+	Pattern string
+	// Extension is the extension of the file name (e.g. ".csv").
+	Extension string
+	// Command is the command of the exec mode (e.g. "psql", "git log").
+	Command string
+	// Content is a regular expression that matches one of the first lines.
+	Content string
+}
+
+// modeRuleLines is the number of lines at the beginning compared with Content.
+const modeRuleLines = 10
+
+// modeRuleTimeout is the time to wait for the lines compared with Content.
+const modeRuleTimeout = 2 * time.Second
+
+// matchName returns true if the file name or command of the document matches the rule.
+func (rule ModeRule) matchName(m *Document) bool {
+	if rule.Pattern != "" {
+		if ok, _ := filepath.Match(rule.Pattern, filepath.Base(m.FileName)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(rule.Pattern, m.FileName); ok {
+			return true
+		}
+	}
+	if rule.Extension != "" && m.commandLine == "" {
+		ext := "." + strings.TrimPrefix(rule.Extension, ".")
+		if strings.EqualFold(filepath.Ext(m.FileName), ext) {
+			return true
+		}
+	}
+	if rule.Command != "" && matchCommand(rule.Command, m.commandLine) {
+		return true
+	}
+	return false
+}
+
+// matchCommand returns true if the command line starts with the command.
+// The first word of the command line is compared by the base name.
+func matchCommand(command string, commandLine string) bool {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return false
+	}
+	fields[0] = filepath.Base(fields[0])
+	words := strings.Fields(command)
+	if len(words) == 0 || len(words) > len(fields) {
+		return false
+	}
+	for i, w := range words {
+		if w != fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchContent returns true if one of the lines matches the Content of the rule.
+func (rule ModeRule) matchContent(lines []string) bool {
+	if rule.Content == "" {
+		return false
+	}
+	re, err := regexp.Compile(rule.Content)
+	if err != nil {
+		log.Printf("mode rule %s: %s", rule.Mode, err)
+		return false
+	}
+	for _, line := range lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyModeRule applies the mode of the first rule that matches the file name or command.
+// If none matches, the rules of Content are applied after the first lines are read.
+// The rules are not applied if the view mode is specified.
+func (root *Root) applyModeRule(m *Document) {
+	if root.Config.ViewMode != "" || m.parent != nil {
+		return
+	}
+	hasContent := false
+	for _, rule := range root.Config.ModeRule {
+		if rule.matchName(m) {
+			root.applyMode(m, rule.Mode)
+			return
+		}
+		if rule.Content != "" {
+			hasContent = true
+		}
+	}
+	if hasContent {
+		go root.waitModeRuleContent(m)
+	}
+}
+
+// applyMode applies the mode to the document.
+func (root *Root) applyMode(m *Document, modeName string) {
+	c, err := root.modeConfig(modeName)
+	if err != nil {
+		log.Printf("mode rule: %s", err)
+		return
+	}
+	m.general = mergeGeneral(m.general, c)
+	m.modeName = modeName
+	if len(m.ColumnPositions) > 0 {
+		m.ColumnWidth = true
+		m.ColumnMode = true
+	}
+	if m.ColumnAlign {
+		m.ColumnMode = true
+	}
+	m.regexpCompile()
+	m.ClearCache()
+	m.resetAlign()
+	if m.general.Caption != "" {
+		m.Caption = m.general.Caption
+	}
+	log.Printf("mode rule: %s %s", m.FileName, modeName)
+}
+
+// eventModeRule represents the first lines of the document have been read.
+type eventModeRule struct {
+	tcell.EventTime
+	m *Document
+}
+
+// waitModeRuleContent waits for the first lines to be read
+// and fires the eventModeRule event.
+func (root *Root) waitModeRuleContent(m *Document) {
+	timeout := time.After(modeRuleTimeout)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for m.BufEndNum() < modeRuleLines && !m.BufEOF() {
+		select {
+		case <-timeout:
+			if m.BufEndNum() > 0 {
+				root.sendModeRule(m)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+	root.sendModeRule(m)
+}
+
+// sendModeRule fires the eventModeRule event.
+func (root *Root) sendModeRule(m *Document) {
+	ev := &eventModeRule{m: m}
+	ev.SetEventNow()
+	root.postEvent(ev)
+}
+
+// contentModeRule applies the mode of the first rule that matches the first lines.
+func (root *Root) contentModeRule(ctx context.Context, m *Document) {
+	n := min(m.BufEndNum(), modeRuleLines)
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		lines = append(lines, m.LineString(i))
+	}
+	for _, rule := range root.Config.ModeRule {
+		if rule.matchContent(lines) {
+			root.applyMode(m, rule.Mode)
+			if m == root.Doc {
+				root.ViewSync(ctx)
+			}
+			return
+		}
+	}
+}
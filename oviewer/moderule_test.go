@@ -0,0 +1,101 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestModeRule_matchName(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        ModeRule
+		fileName    string
+		commandLine string
+		want        bool
+	}{
+		{
+			name:     "pattern",
+			rule:     ModeRule{Pattern: "*.csv"},
+			fileName: "/tmp/test.csv",
+			want:     true,
+		},
+		{
+			name:     "extension",
+			rule:     ModeRule{Extension: "csv"},
+			fileName: "/tmp/TEST.CSV",
+			want:     true,
+		},
+		{
+			name:     "extensionNotMatch",
+			rule:     ModeRule{Extension: ".csv"},
+			fileName: "/tmp/test.tsv",
+			want:     false,
+		},
+		{
+			name:        "command",
+			rule:        ModeRule{Command: "git log"},
+			fileName:    "STDOUT",
+			commandLine: "/usr/bin/git log --oneline",
+			want:        true,
+		},
+		{
+			name:        "commandNotMatch",
+			rule:        ModeRule{Command: "git log"},
+			fileName:    "STDOUT",
+			commandLine: "git diff",
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Document{FileName: tt.fileName, commandLine: tt.commandLine}
+			if got := tt.rule.matchName(m); got != tt.want {
+				t.Errorf("ModeRule.matchName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModeRule_matchContent(t *testing.T) {
+	lines := []string{" id | name ", "----+------", "  1 | test"}
+	if !(ModeRule{Content: `^-+\+-+`}).matchContent(lines) {
+		t.Errorf("ModeRule.matchContent() = false, want true")
+	}
+	if (ModeRule{Content: `^commit [0-9a-f]+`}).matchContent(lines) {
+		t.Errorf("ModeRule.matchContent() = true, want false")
+	}
+	if (ModeRule{Content: `(`}).matchContent(lines) {
+		t.Errorf("ModeRule.matchContent() = true, want false")
+	}
+}
+
+func TestRoot_applyModeRule(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "MOCK_DATA.csv"), filepath.Join(testdata, "normal.txt"))
+	root.prepareScreen()
+	root.Config.Mode = map[string]general{
+		"csv":   {ColumnDelimiter: ",", ColumnMode: true, Header: 1},
+		"color": {Header: 2},
+	}
+	root.Config.ModeRule = []ModeRule{
+		{Mode: "csv", Extension: "csv"},
+		{Mode: "color", Content: "^khaki"},
+	}
+	csv := root.DocList[0]
+	root.applyModeRule(csv)
+	if !csv.ColumnMode || csv.ColumnDelimiter != "," || csv.Header != 1 {
+		t.Errorf("applyModeRule() ColumnMode = %v, ColumnDelimiter = %v, Header = %v", csv.ColumnMode, csv.ColumnDelimiter, csv.Header)
+	}
+
+	normal := root.DocList[1]
+	root.contentModeRule(context.Background(), normal)
+	if normal.Header != 2 {
+		t.Errorf("contentModeRule() Header = %v, want %v", normal.Header, 2)
+	}
+}
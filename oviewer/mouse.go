@@ -6,7 +6,6 @@ import (
 	"log"
 	"strings"
 
-	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
 	"github.com/mattn/go-runewidth"
 )
@@ -160,10 +159,7 @@ func (root *Root) copyToClipboard(_ context.Context) {
 	if len(str) == 0 {
 		return
 	}
-	if err := clipboard.WriteAll(str); err != nil {
-		log.Printf("copyToClipboard: %v", err)
-	}
-	root.setMessage("Copy")
+	root.copyClipboard(str, "Copy")
 }
 
 type eventPaste struct {
@@ -188,7 +184,7 @@ func (root *Root) pasteFromClipboard(context.Context) {
 		return
 	}
 
-	str, err := clipboard.ReadAll()
+	str, err := root.readClipboard()
 	if err != nil {
 		log.Printf("pasteFromClipboard: %v", err)
 		return
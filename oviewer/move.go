@@ -31,7 +31,9 @@ func (root *Root) movePgUp(context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	root.Doc.movePgUp()
+	for i := 0; i < root.repeatCount(); i++ {
+		root.Doc.movePgUp()
+	}
 }
 
 // Moves down one screen.
@@ -39,7 +41,9 @@ func (root *Root) movePgDn(context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	root.Doc.movePgDn()
+	for i := 0; i < root.repeatCount(); i++ {
+		root.Doc.movePgDn()
+	}
 }
 
 // Moves up half a screen.
@@ -47,7 +51,9 @@ func (root *Root) moveHfUp(context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	root.Doc.moveHfUp()
+	for i := 0; i < root.repeatCount(); i++ {
+		root.Doc.moveHfUp()
+	}
 }
 
 // Moves down half a screen.
@@ -55,17 +61,39 @@ func (root *Root) moveHfDn(context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	root.Doc.moveHfDn()
+	for i := 0; i < root.repeatCount(); i++ {
+		root.Doc.moveHfDn()
+	}
 }
 
 // Move up one line.
-func (root *Root) moveUpOne(context.Context) {
-	root.moveUp(1)
+// In visual mode, the cursor line moves instead of the screen.
+// In the record view, it moves to the previous record at the top of the record.
+func (root *Root) moveUpOne(ctx context.Context) {
+	if root.visual.active {
+		root.visualMoveY(-root.repeatCount())
+		return
+	}
+	if root.Doc.documentType == DocRecord && root.Doc.isTop() {
+		root.moveRecord(ctx, -root.repeatCount())
+		return
+	}
+	root.moveUp(root.repeatCount())
 }
 
 // Move down one line.
-func (root *Root) moveDownOne(context.Context) {
-	root.moveDown(1)
+// In visual mode, the cursor line moves instead of the screen.
+// In the record view, it moves to the next record at the bottom of the record.
+func (root *Root) moveDownOne(ctx context.Context) {
+	if root.visual.active {
+		root.visualMoveY(root.repeatCount())
+		return
+	}
+	if root.Doc.documentType == DocRecord && root.Doc.isBottom() {
+		root.moveRecord(ctx, root.repeatCount())
+		return
+	}
+	root.moveDown(root.repeatCount())
 }
 
 // Move up by n amount.
@@ -89,7 +117,7 @@ func (root *Root) nextSection(ctx context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	if err := root.Doc.moveNextSection(ctx); err != nil {
+	if err := root.Doc.moveNextSectionN(ctx, root.repeatCount()); err != nil {
 		// Move by page if there is no section.
 		root.Doc.movePgDn()
 		// Last section or no section.
@@ -104,7 +132,7 @@ func (root *Root) prevSection(ctx context.Context) {
 	root.resetSelect()
 	defer root.releaseEventBuffer()
 
-	if err := root.Doc.movePrevSection(ctx); err != nil {
+	if err := root.Doc.movePrevSectionN(ctx, root.repeatCount()); err != nil {
 		// Move by page, if there is no section delimiter.
 		root.Doc.movePgUp()
 		// First section or no section.
@@ -124,12 +152,22 @@ func (root *Root) lastSection(ctx context.Context) {
 }
 
 // Move to the left.
+// In block visual mode, the cursor moves instead of the screen.
 func (root *Root) moveLeftOne(context.Context) {
+	if root.visual.active && root.visual.rectangle {
+		root.visualMoveX(-1)
+		return
+	}
 	root.moveLeft(1)
 }
 
 // Move to the right.
+// In block visual mode, the cursor moves instead of the screen.
 func (root *Root) moveRightOne(context.Context) {
+	if root.visual.active && root.visual.rectangle {
+		root.visualMoveX(1)
+		return
+	}
 	root.moveRight(1)
 }
 
@@ -42,17 +42,18 @@ func (m *Document) optimalCursorWidth(scr SCR, cursor int) int {
 	if !ok || !line.valid {
 		return cursor
 	}
-	return optimalCursor(line, m.columnWidths, cursor, m.x, m.x+m.width)
+	return optimalCursor(line, m.viewColumnWidths(), cursor, m.x, m.x+m.width)
 }
 
 // optimalCursorDelimiter returns the optimal cursor position when in columnDelimiter mode.
 func (m *Document) optimalCursorDelimiter(scr SCR, cursor int) int {
 	for i := 0; i < m.firstLine()+TargetLineDelimiter; i++ {
-		line, ok := scr.lines[m.topLN+m.firstLine()+i]
+		lN := m.topLN + m.firstLine() + i
+		line, ok := scr.lines[lN]
 		if !ok || !line.valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.columnStarts(lN, line.str)
 		if len(widths) <= cursor {
 			continue
 		}
@@ -109,23 +110,35 @@ func (m *Document) optimalX(scr SCR, cursor int) (int, error) {
 
 // optimalXWidth returns the optimal x position of the column at the specified cursor position.
 func (m *Document) optimalXWidth(cursor int) (int, error) {
-	if len(m.columnWidths) == 0 {
+	columnWidths := m.viewColumnWidths()
+	if len(columnWidths) == 0 {
 		return 0, ErrNoColumn
 	}
-	cursor = min(cursor, len(m.columnWidths)) - 1
-	return m.columnWidths[cursor], nil
+	if cursor < m.ColumnFreeze {
+		return m.x, nil
+	}
+	cursor = min(cursor, len(columnWidths)) - 1
+	x := columnWidths[cursor]
+	if m.ColumnFreeze > 0 && m.ColumnFreeze <= len(columnWidths) {
+		x -= columnWidths[m.ColumnFreeze-1]
+	}
+	return max(0, x), nil
 }
 
 // optimalXDelimiter returns the best x position of the column at the specified cursor position.
 func (m *Document) optimalXDelimiter(scr SCR, cursor int) (int, error) {
 	for i := 0; i < m.firstLine()+TargetLineDelimiter; i++ {
-		line, ok := scr.lines[m.topLN+m.firstLine()+i]
+		lN := m.topLN + m.firstLine() + i
+		line, ok := scr.lines[lN]
 		if !ok || !line.valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.columnStarts(lN, line.str)
 		if cursor > 0 && cursor < len(widths) {
-			return line.pos.x(widths[cursor]) - columnMargin, nil
+			if cursor < m.ColumnFreeze {
+				return m.x, nil
+			}
+			return max(0, line.pos.x(widths[cursor])-columnMargin-line.frozen), nil
 		}
 	}
 	return 0, ErrNoColumn
@@ -148,9 +161,10 @@ func (m *Document) moveToWidth(scr SCR, moveTo int) (int, int, error) {
 		return m.x, m.columnCursor, ErrNoColumn
 	}
 
-	widths := make([]int, 0, len(m.columnWidths)+2)
+	columnWidths := m.viewColumnWidths()
+	widths := make([]int, 0, len(columnWidths)+2)
 	widths = append(widths, 0)
-	widths = append(widths, m.columnWidths...)
+	widths = append(widths, columnWidths...)
 	var cl, cr int
 	if cursor < len(widths)-1 {
 		cl = widths[cursor]
@@ -159,7 +173,11 @@ func (m *Document) moveToWidth(scr SCR, moveTo int) (int, int, error) {
 		cl = widths[len(widths)-1]
 		cr = m.rightmost(scr)
 	}
-	return screenAdjustX(m.x, m.x+m.width, cl, cr, widths, cursor)
+	frozen := 0
+	if m.ColumnFreeze > 0 && m.ColumnFreeze < len(widths) {
+		frozen = widths[m.ColumnFreeze]
+	}
+	return m.frozenAdjustX(frozen, m.width, cl, cr, widths, cursor)
 }
 
 // moveToDelimiter returns x and cursor from the orientation to move.
@@ -173,11 +191,12 @@ func (m *Document) moveToDelimiter(scr SCR, moveTo int) (int, int, error) {
 	cursor := max(0, m.columnCursor+moveTo)
 	// m.firstLine()+TargetLineDelimiter = Maximum columnMode target.
 	for i := 0; i < m.firstLine()+TargetLineDelimiter; i++ {
-		line, ok := scr.lines[m.topLN+m.firstLine()+i]
+		lN := m.topLN + m.firstLine() + i
+		line, ok := scr.lines[lN]
 		if !ok || !line.valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.columnStarts(lN, line.str)
 		maxColumn = max(maxColumn, len(widths)-1)
 		if len(widths) == 0 {
 			continue
@@ -189,12 +208,12 @@ func (m *Document) moveToDelimiter(scr SCR, moveTo int) (int, int, error) {
 			if cursor < len(widths)-1 {
 				cr = line.pos.x(widths[cursor+1])
 			}
-			return screenAdjustX(m.x, m.x+width, cl, cr, widths, cursor)
+			return m.frozenAdjustX(line.frozen, width, cl, cr, widths, cursor)
 		}
 		// rightmost column.
 		cl := line.pos.x(widths[len(widths)-1])
 		cr := line.pos.x(len(line.str))
-		return screenAdjustX(m.x, m.x+width, cl, cr, widths, cursor)
+		return m.frozenAdjustX(line.frozen, width, cl, cr, widths, cursor)
 	}
 
 	if maxColumn > 0 {
@@ -266,7 +285,11 @@ func screenAdjustX(left int, right int, cl int, cr int, widths []int, cursor int
 
 // splitByDelimiter return a slice split by delimiter.
 func splitByDelimiter(str string, delimiter string, delimiterReg *regexp.Regexp) []int {
-	indexes := allIndex(str, delimiter, delimiterReg)
+	return delimiterWidths(str, allIndex(str, delimiter, delimiterReg))
+}
+
+// delimiterWidths returns the start positions of the columns from the positions of the delimiters.
+func delimiterWidths(str string, indexes [][]int) []int {
 	if len(indexes) == 0 {
 		return nil
 	}
@@ -371,15 +394,15 @@ func (m *Document) rightmost(scr SCR) int {
 // rightmostColumn returns the number of rightmost columns.
 func (m *Document) rightmostColumn(scr SCR) int {
 	if m.ColumnWidth {
-		return len(m.columnWidths)
+		return len(m.viewColumnWidths())
 	}
 
 	maxColumn := 0
-	for _, line := range scr.lines {
+	for lN, line := range scr.lines {
 		if !line.valid {
 			continue
 		}
-		widths := splitByDelimiter(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
+		widths := m.columnStarts(lN, line.str)
 		maxColumn = max(maxColumn, len(widths)-1)
 	}
 	return maxColumn
@@ -273,6 +273,34 @@ func (m *Document) moveNextSection(ctx context.Context) error {
 	return nil
 }
 
+// moveNextSectionN moves to the n-th next section.
+// If there are fewer sections, it stops at the last section that could be moved.
+func (m *Document) moveNextSectionN(ctx context.Context, n int) error {
+	for i := 0; i < n; i++ {
+		if err := m.moveNextSection(ctx); err != nil {
+			if i == 0 {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// movePrevSectionN moves to the n-th previous section.
+// If there are fewer sections, it stops at the first section that could be moved.
+func (m *Document) movePrevSectionN(ctx context.Context, n int) error {
+	for i := 0; i < n; i++ {
+		if err := m.movePrevSection(ctx); err != nil {
+			if i == 0 {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
 // movePrevSection moves to the previous section.
 func (m *Document) movePrevSection(ctx context.Context) error {
 	return m.movePrevSectionLN(ctx, m.topLN)
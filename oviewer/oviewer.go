@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"regexp"
 	"sync"
 	"syscall"
+	"time"
 
 	"code.rocketnine.space/tslocum/cbind"
 	"github.com/fsnotify/fsnotify"
@@ -33,12 +35,52 @@ type Root struct {
 	input *Input
 	// cancelFunc saves the cancel function, which is a time-consuming process.
 	cancelFunc context.CancelFunc
+	// jobCancel cancels the running background job.
+	jobCancel context.CancelFunc
+	// jobID is the ID of the last background job.
+	jobID int
 
 	// searcher is the searcher.
 	searcher Searcher
 
+	// reloadDoc is the document reloaded after the editor exits.
+	reloadDoc *Document
+	// reloadFilter is the filter document that is applied again to reloadDoc.
+	reloadFilter *Document
+
+	// visual is the keyboard-driven selection.
+	visual visualSelect
+	// countPending is the count prefix being entered.
+	countPending int
+	// count is the count prefix passed to the running action.
+	count int
+	// remoteListener is the listener of the remote control socket.
+	remoteListener net.Listener
+	// macro is the state of the macro recording.
+	macro macroRecorder
+	// clipboardRegister is the internal register that holds the last copied string.
+	clipboardRegister string
+	// configFiles is the absolute paths of the config files to watch.
+	configFiles []string
+	// configLoader reads the config files again.
+	configLoader func() (Config, []string, error)
+	// configWatcher is the watcher of Run that watches the config files.
+	configWatcher *fsnotify.Watcher
+	// configTimer delays the reload until the writes of the config files finish.
+	configTimer *time.Timer
+
 	// keyConfig contains the binding settings for the key.
 	keyConfig *cbind.Configuration
+	// keyBinds is the key bindings set in keyConfig.
+	keyBinds KeyBind
+	// keySequences is the actions assigned to key sequences.
+	keySequences []keySequence
+	// keyPending is the keys of the key sequence being entered.
+	keyPending []*tcell.EventKey
+	// keySequenceGen identifies the pending key sequence for the timeout.
+	keySequenceGen int
+	// keyLetter is the letter entered for <letter> of the key sequence being run.
+	keyLetter rune
 	// inputKeyConfig contains the binding settings for the key.
 	inputKeyConfig *cbind.Configuration
 
@@ -164,6 +206,26 @@ type general struct {
 	ColumnMode bool
 	// ColumnWidth is column width mode.
 	ColumnWidth bool
+	// ColumnCSV parses the columns as CSV (RFC 4180) with quoted fields.
+	// It is always enabled when the column delimiter is "," or tab.
+	ColumnCSV bool
+	// ColumnAlign lays out the delimited columns as an aligned table.
+	ColumnAlign bool
+	// ColumnAlignSeparator is the separator between the aligned columns.
+	ColumnAlignSeparator string
+	// ColumnMaxWidth is the maximum width of the aligned columns (0 is unlimited).
+	ColumnMaxWidth int
+	// ColumnHide is the columns to hide (numbered from 1).
+	ColumnHide []int
+	// ColumnOrder is the columns displayed first, in this order (numbered from 1).
+	ColumnOrder []int
+	// ColumnFreeze is the number of columns on the left that are not scrolled.
+	ColumnFreeze int
+	// ColumnPositions is the start positions of the columns in the ColumnWidth mode (numbered from 1).
+	// The columns are not guessed if it is specified.
+	ColumnPositions []int
+	// Column is the column selected at startup (the name in the header or the number from 1).
+	Column string
 	// ColumnRainbow is column rainbow.
 	ColumnRainbow bool
 	// LineNumMode displays line numbers.
@@ -213,6 +275,15 @@ type Config struct {
 	ViewMode string
 	// Default keybindings. Disabled if the default keybinding is "disable".
 	DefaultKeyBind string
+	// KeyBindPreset is the key binding preset (default, less, vim, emacs).
+	// Keybind is set on top of the preset.
+	KeyBindPreset string
+	// Macro is the named macros of the recorded actions.
+	Macro map[string][]MacroStep
+	// MacroKeybind is the keys to play each macro.
+	MacroKeybind map[string][]string
+	// KeySequenceTimeout is the time (milliseconds) to wait for the next key of a key sequence.
+	KeySequenceTimeout int
 	// StyleColumnRainbow  is the style that applies to the column rainbow color highlight.
 	StyleColumnRainbow []OVStyle
 	// StyleMultiColorHighlight is the style that applies to the multi color highlight.
@@ -272,6 +343,19 @@ type Config struct {
 
 	// DisableColumnCycle is disable column cycle.
 	DisableColumnCycle bool
+	// ClipboardMethod is the method to copy to the clipboard.
+	// auto, system, osc52, tmux, command or internal.
+	ClipboardMethod string
+	// ClipboardCopyCommand is the command that receives the copied string on standard input.
+	ClipboardCopyCommand []string
+	// ClipboardPasteCommand is the command that outputs the string to paste.
+	ClipboardPasteCommand []string
+	// ModeRule is the rules that select the view mode of the document automatically.
+	ModeRule []ModeRule
+	// Preprocessor is the commands that convert the file before it is displayed.
+	Preprocessor []Preprocessor
+	// EditorLineArg is the argument template of the editor ({line} and {file} are replaced).
+	EditorLineArg string
 	// Debug represents whether to enable the debug output.
 	Debug bool
 }
@@ -328,6 +412,8 @@ var (
 	OverLineStyle tcell.Style
 	// SkipExtract is a flag to skip extracting compressed files.
 	SkipExtract bool
+	// Preprocessors are the commands that convert the file before it is displayed.
+	Preprocessors []Preprocessor
 )
 
 // ov output destination.
@@ -398,6 +484,52 @@ var (
 	ErrAlreadyLoaded = errors.New("chunk already loaded")
 	// ErrEvictedMemory indicates that it has been evicted from memory.
 	ErrEvictedMemory = errors.New("evicted memory")
+	// ErrKeySequenceConflict indicates that the key sequences conflict.
+	ErrKeySequenceConflict = errors.New("key sequence conflict")
+	// ErrMacroNotFound indicates that the macro is not found.
+	ErrMacroNotFound = errors.New("macro not found")
+	// ErrNoTty indicates that the terminal cannot be written directly.
+	ErrNoTty = errors.New("no tty")
+	// ErrNoClipboardCommand indicates that the clipboard command is not set.
+	ErrNoClipboardCommand = errors.New("no clipboard command")
+	// ErrNotEditable indicates that the document is not a file that can be edited.
+	ErrNotEditable = errors.New("not an editable file")
+	// ErrNoEditor indicates that the editor is not set.
+	ErrNoEditor = errors.New("no editor")
+	// ErrNoMarked indicates that there are no marked lines.
+	ErrNoMarked = errors.New("no marked lines")
+	// ErrInvalidPipeSource indicates that the input of the pipe is invalid.
+	ErrInvalidPipeSource = errors.New("invalid pipe source")
+	// ErrAlreadyListening indicates that another process is listening on the socket.
+	ErrAlreadyListening = errors.New("already listening")
+	// ErrUnknownCommand indicates that the remote command is unknown.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrRemoteTimeout indicates that the remote query timed out.
+	ErrRemoteTimeout = errors.New("remote query timed out")
+	// ErrUnknownClipboard indicates an unknown clipboard method.
+	ErrUnknownClipboard = errors.New("unknown clipboard method")
+	// ErrInvalidColor indicates that the color name is invalid.
+	ErrInvalidColor = errors.New("invalid color")
+	// ErrInvalidRegexp indicates that the regular expression is invalid.
+	ErrInvalidRegexp = errors.New("invalid regular expression")
+	// ErrUnknownAction indicates that the action name is unknown.
+	ErrUnknownAction = errors.New("unknown action")
+	// ErrKeyBindConflict indicates that the key is assigned to multiple actions.
+	ErrKeyBindConflict = errors.New("key conflict")
+	// ErrUnknownMode indicates that the view mode is not defined.
+	ErrUnknownMode = errors.New("unknown mode")
+	// ErrUnknownSortType indicates that the sort type is unknown.
+	ErrUnknownSortType = errors.New("unknown sort type")
+	// ErrNotColumnMode indicates that the column operation is not in the column mode.
+	ErrNotColumnMode = errors.New("not column mode")
+	// ErrLastColumn indicates that the last displayed column cannot be hidden.
+	ErrLastColumn = errors.New("cannot hide the last column")
+	// ErrNoMoreRecord indicates that there are no more records in the record view.
+	ErrNoMoreRecord = errors.New("no more records")
+	// ErrHiddenColumn indicates that the column is hidden.
+	ErrHiddenColumn = errors.New("hidden column")
+	// ErrUnknownExportTarget indicates that the target of the column export is unknown.
+	ErrUnknownExportTarget = errors.New("unknown export target")
 )
 
 // This is a function of tcell.NewScreen but can be replaced with mock.
@@ -565,6 +697,9 @@ func (root *Root) watchEvent(event fsnotify.Event) {
 	root.mu.Lock()
 	defer root.mu.Unlock()
 
+	if root.watchConfigEvent(event) {
+		return
+	}
 	for _, m := range root.DocList {
 		if m.filepath == event.Name {
 			switch event.Op {
@@ -594,6 +729,7 @@ func (root *Root) setKeyConfig(ctx context.Context) (map[string][]string, error)
 	if err := root.setHandlers(ctx, keyBind); err != nil {
 		return nil, err
 	}
+	root.keyBinds = keyBind
 
 	keys, ok := keyBind[actionCancel]
 	if !ok {
@@ -616,12 +752,14 @@ func (root *Root) Run() error {
 	defer cancel()
 
 	defer root.Close()
+	defer root.closeRemote()
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return fmt.Errorf("failed to create watcher: %w", err)
 	}
 	defer watcher.Close()
 	root.SetWatcher(watcher)
+	root.watchConfig(watcher)
 
 	// Do not set the key bindings in NewOviewer
 	// because it is done after loading the config.
@@ -648,11 +786,15 @@ func (root *Root) Run() error {
 	for n, doc := range root.DocList {
 		doc.general = root.Config.General
 		doc.regexpCompile()
+		root.applyModeRule(doc)
 
 		if doc.FollowName {
 			doc.FollowMode = true
 		}
-		if doc.ColumnWidth {
+		if len(doc.ColumnPositions) > 0 {
+			doc.ColumnWidth = true
+		}
+		if doc.ColumnWidth || doc.ColumnAlign {
 			doc.ColumnMode = true
 		}
 		w := ""
@@ -873,6 +1015,33 @@ func mergeGeneral(src general, dst general) general {
 	if dst.ColumnRainbow {
 		src.ColumnRainbow = dst.ColumnRainbow
 	}
+	if dst.ColumnCSV {
+		src.ColumnCSV = dst.ColumnCSV
+	}
+	if dst.ColumnAlign {
+		src.ColumnAlign = dst.ColumnAlign
+	}
+	if dst.ColumnAlignSeparator != "" {
+		src.ColumnAlignSeparator = dst.ColumnAlignSeparator
+	}
+	if dst.ColumnMaxWidth != 0 {
+		src.ColumnMaxWidth = dst.ColumnMaxWidth
+	}
+	if len(dst.ColumnHide) > 0 {
+		src.ColumnHide = dst.ColumnHide
+	}
+	if len(dst.ColumnOrder) > 0 {
+		src.ColumnOrder = dst.ColumnOrder
+	}
+	if dst.ColumnFreeze != 0 {
+		src.ColumnFreeze = dst.ColumnFreeze
+	}
+	if len(dst.ColumnPositions) > 0 {
+		src.ColumnPositions = dst.ColumnPositions
+	}
+	if dst.Column != "" {
+		src.Column = dst.Column
+	}
 	if dst.LineNumMode {
 		src.LineNumMode = dst.LineNumMode
 	}
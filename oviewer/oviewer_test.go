@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/spf13/viper"
@@ -22,6 +23,18 @@ func fakeScreen() (tcell.Screen, error) {
 	return tcell.NewSimulationScreen(""), nil
 }
 
+// eofHelper waits for the document to read all the lines.
+func eofHelper(t *testing.T, m *Document) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if m.BufEOF() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("read timeout")
+}
+
 func rootHelper(t *testing.T) *Root {
 	t.Helper()
 	tcellNewScreen = fakeScreen
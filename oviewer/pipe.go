@@ -0,0 +1,138 @@
+package oviewer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// The input of the pipe command.
+const (
+	pipeAll       = "all"
+	pipeScreen    = "screen"
+	pipeSelection = "selection"
+	pipeMarked    = "marked"
+)
+
+// pipeSources is the order in which the input is switched by the tab key.
+var pipeSources = []string{pipeAll, pipeScreen, pipeSelection, pipeMarked}
+
+// nextPipeSource returns the next input of the pipe command.
+func nextPipeSource(source string) string {
+	for i, s := range pipeSources {
+		if s == source {
+			return pipeSources[(i+1)%len(pipeSources)]
+		}
+	}
+	return pipeSources[0]
+}
+
+// defaultPipeSource returns the selection if there is a visual selection,
+// the marked lines if there are marks, otherwise the whole document.
+func (root *Root) defaultPipeSource() string {
+	if root.visual.active {
+		return pipeSelection
+	}
+	if len(root.Doc.marked) > 0 {
+		return pipeMarked
+	}
+	return pipeAll
+}
+
+// pipeCommand runs the shell command with the input
+// and opens the output as a new document.
+func (root *Root) pipeCommand(ctx context.Context, source string, str string) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return
+	}
+	input, err := root.pipeInput(ctx, source)
+	if err != nil {
+		root.setMessageLogf("pipe: %s", err)
+		return
+	}
+	// An empty input is passed as empty, not the standard input of ov.
+	if input == nil {
+		input = []byte{}
+	}
+
+	command := NewCommand(shellCommand(str)...)
+	command.stdin = input
+	if err := command.start(); err != nil {
+		root.setMessageLogf("pipe: %s", err)
+		return
+	}
+	command.docout.commandLine = str
+	command.docerr.commandLine = str
+	command.docout.Caption = "(" + str + ")" + command.docout.FileName
+	command.docerr.Caption = "(" + str + ")" + command.docerr.FileName
+
+	root.addDocument(ctx, command.docout)
+	num := root.CurrentDoc
+	root.addDocument(ctx, command.docerr)
+	// Display the output, not the error output.
+	root.setDocumentNum(ctx, num)
+}
+
+// shellCommand returns the arguments to run the string in the shell.
+func shellCommand(str string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"CMD.EXE", "/C", str}
+	}
+	return []string{"/bin/sh", "-c", str}
+}
+
+// pipeInput returns the input of the pipe command.
+// The whole document is read including the chunks that are not loaded into memory.
+func (root *Root) pipeInput(ctx context.Context, source string) ([]byte, error) {
+	m := root.Doc
+	var buf bytes.Buffer
+	switch source {
+	case pipeAll:
+		err := m.eachLine(ctx, 0, m.BufEndNum(), func(_ int, line []byte) error {
+			buf.Write(line)
+			buf.WriteByte('\n')
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case pipeScreen:
+		start := m.topLN + m.firstLine()
+		end := max(start, m.bottomLN-1)
+		if err := m.Export(&buf, start, end); err != nil {
+			return nil, err
+		}
+	case pipeSelection:
+		if !root.visual.active {
+			// The mouse selection has been copied to the register.
+			return []byte(root.clipboardRegister), nil
+		}
+		str, err := root.visualString()
+		root.endVisual()
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(str)
+	case pipeMarked:
+		if len(m.marked) == 0 {
+			return nil, ErrNoMarked
+		}
+		marked := slices.Clone(m.marked)
+		slices.Sort(marked)
+		for _, lN := range marked {
+			line, err := m.LineStr(lN)
+			if err != nil {
+				return nil, err
+			}
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPipeSource, source)
+	}
+	return buf.Bytes(), nil
+}
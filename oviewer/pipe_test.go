@@ -0,0 +1,112 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_nextPipeSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: pipeAll, want: pipeScreen},
+		{source: pipeMarked, want: pipeAll},
+		{source: "unknown", want: pipeAll},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := nextPipeSource(tt.source); got != tt.want {
+				t.Errorf("nextPipeSource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_pipeInput(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.prepareScreen()
+	ctx := context.Background()
+	root.draw(ctx)
+	m := root.Doc
+
+	all, err := root.pipeInput(ctx, pipeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(all), "\n"); got != m.BufEndNum() {
+		t.Errorf("pipeInput(all) lines = %v, want %v", got, m.BufEndNum())
+	}
+
+	screen, err := root.pipeInput(ctx, pipeScreen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(screen), "\n"); got != m.bottomLN-m.topLN {
+		t.Errorf("pipeInput(screen) lines = %v, want %v", got, m.bottomLN-m.topLN)
+	}
+
+	if _, err := root.pipeInput(ctx, pipeMarked); err == nil {
+		t.Errorf("pipeInput(marked) error = nil, want %v", ErrNoMarked)
+	}
+	m.marked = []int{3, 1}
+	marked, err := root.pipeInput(ctx, pipeMarked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := m.LineString(1) + "\n" + m.LineString(3) + "\n"
+	if string(marked) != want {
+		t.Errorf("pipeInput(marked) = %q, want %q", marked, want)
+	}
+}
+
+func TestRoot_pipeInputLarge(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "test3.txt"))
+	m := root.Doc
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Fatal("chunk 1 is loaded")
+	}
+	all, err := root.pipeInput(context.Background(), pipeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(all), "\n"), "\n")
+	if len(lines) != 12345 || lines[ChunkSize] != "10001" || lines[len(lines)-1] != "12345" {
+		t.Errorf("pipeInput(all) lines = %d, want all lines of the file", len(lines))
+	}
+	// The chunks are not loaded into memory.
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Error("pipeInput(all) loaded chunk 1")
+	}
+}
+
+func TestRoot_pipeCommand(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	root.prepareScreen()
+	root.pipeCommand(context.Background(), pipeAll, "cat")
+	if got := root.DocumentLen(); got != 3 {
+		t.Fatalf("DocumentLen() = %v, want %v", got, 3)
+	}
+	if root.CurrentDoc != 1 {
+		t.Errorf("CurrentDoc = %v, want %v", root.CurrentDoc, 1)
+	}
+	if root.Doc.Caption != "(cat)STDOUT" {
+		t.Errorf("Caption = %v, want %v", root.Doc.Caption, "(cat)STDOUT")
+	}
+}
@@ -81,12 +81,17 @@ func (root *Root) prepareDraw(ctx context.Context) {
 		root.Doc.topLX, root.Doc.topLN = tX, tN-root.scr.headerEnd
 		root.Doc.showGotoF = false
 	}
-	if root.Doc.ColumnWidth && len(root.Doc.columnWidths) == 0 {
+	if root.Doc.ColumnWidth && (len(root.Doc.columnWidths) == 0 || len(root.Doc.ColumnPositions) > 0) {
 		root.Doc.setColumnWidths(root.scr)
 	}
+	root.Doc.prepareAlign()
 
 	// Prepare the lines.
 	root.scr.lines = root.prepareLines(root.scr.lines)
+
+	if !root.Doc.columnSelected {
+		root.selectStartColumn()
+	}
 }
 
 // shiftBody shifts the section header so that it is not hidden by it.
@@ -189,7 +194,7 @@ func (root *Root) setLines(lines map[int]LineC, startLN int, endLN int) map[int]
 		line := m.getLineC(lN, m.TabWidth)
 		if line.valid {
 			RangeStyle(line.lc, 0, len(line.lc), root.StyleBody)
-			root.styleContent(line)
+			root.styleContent(lN, line)
 		}
 		lines[lN] = line
 	}
@@ -254,12 +259,12 @@ func lineNumbers(s map[int]LineC) []int {
 }
 
 // styleContent applies the style of the content.
-func (root *Root) styleContent(line LineC) {
+func (root *Root) styleContent(lN int, line LineC) {
 	if root.Doc.PlainMode {
 		root.plainStyle(line.lc)
 	}
 	if root.Doc.ColumnMode {
-		root.columnHighlight(line)
+		root.columnHighlight(lN, line)
 	}
 	root.multiColorHighlight(line)
 	root.searchHighlight(line)
@@ -273,18 +278,25 @@ func (*Root) plainStyle(lc contents) {
 }
 
 // columnHighlight applies the style of the column highlight.
-func (root *Root) columnHighlight(line LineC) {
+func (root *Root) columnHighlight(lN int, line LineC) {
+	if line.columns != nil {
+		root.columnLayoutHighlight(line)
+		return
+	}
 	if root.Doc.ColumnWidth {
 		root.columnWidthHighlight(line)
 		return
 	}
-	root.columnDelimiterHighlight(line)
+	root.columnDelimiterHighlight(lN, line)
 }
 
 // multiColorHighlight applies styles to multiple words (regular expressions) individually.
 // The style of the first specified word takes precedence.
 func (root *Root) multiColorHighlight(line LineC) {
 	numC := len(root.StyleMultiColorHighlight)
+	if numC == 0 {
+		return
+	}
 	for i := len(root.Doc.multiColorRegexps) - 1; i >= 0; i-- {
 		indexes := searchPositionReg(line.str, root.Doc.multiColorRegexps[i])
 		for _, idx := range indexes {
@@ -306,51 +318,42 @@ func (root *Root) searchHighlight(line LineC) {
 	}
 }
 
-// columnHighlight applies the style of the column highlight.
-func (root *Root) columnDelimiterHighlight(line LineC) {
+// columnDelimiterHighlight applies the style of the column highlight.
+func (root *Root) columnDelimiterHighlight(lN int, line LineC) {
 	m := root.Doc
-	indexes := allIndex(line.str, m.ColumnDelimiter, m.ColumnDelimiterReg)
-	if len(indexes) == 0 {
+	indexes, column := m.columnIndexes(lN, line.str)
+	if len(indexes) == 0 && column == 0 {
 		return
 	}
 
 	numC := len(root.StyleColumnRainbow)
 
-	lStart := 0
-	if indexes[0][0] == 0 {
+	iStart := 0
+	// The leftmost fence is not a delimiter.
+	if column == 0 && indexes[0][0] == 0 {
 		if len(indexes) == 1 {
 			return
 		}
-		lStart = indexes[0][1]
+		iStart = indexes[0][1]
 		indexes = indexes[1:]
 	}
 
-	var iStart, iEnd int
 	for c := 0; c < len(indexes)+1; c++ {
-		switch {
-		case c == 0 && lStart == 0:
-			iStart = lStart
-			iEnd = indexes[0][1] - len(m.ColumnDelimiter)
-			if iEnd < 0 {
-				iEnd = 0
-			}
-		case c < len(indexes):
-			iStart = iEnd + 1
+		iEnd := len(line.str)
+		if c < len(indexes) {
 			iEnd = indexes[c][0]
-		case c == len(indexes):
-			iStart = iEnd + 1
-			iEnd = len(line.str)
-		}
-		if iStart < 0 || iEnd < 0 {
-			return
 		}
 		start, end := line.pos.x(iStart), line.pos.x(iEnd)
-		if m.ColumnRainbow {
-			RangeStyle(line.lc, start, end, root.StyleColumnRainbow[c%numC])
+		n := column + c
+		if m.ColumnRainbow && numC > 0 {
+			RangeStyle(line.lc, start, end, root.StyleColumnRainbow[n%numC])
 		}
-		if c == m.columnCursor {
+		if n == m.columnCursor {
 			RangeStyle(line.lc, start, end, root.StyleColumnHighlight)
 		}
+		if c < len(indexes) {
+			iStart = indexes[c][1]
+		}
 	}
 }
 
@@ -364,16 +367,26 @@ func (root *Root) columnWidthHighlight(line LineC) {
 
 	numC := len(root.StyleColumnRainbow)
 
-	start, end := -1, -1
-	for c := 0; c < len(indexes)+1; c++ {
-		start = end + 1
-		end = findColumnEnd(line.lc, indexes, c)
+	for c, r := range m.columnWidthRanges(line.lc) {
+		if m.ColumnRainbow && numC > 0 {
+			RangeStyle(line.lc, r[0], r[1], root.StyleColumnRainbow[c%numC])
+		}
+		if c == m.columnCursor {
+			RangeStyle(line.lc, r[0], r[1], root.StyleColumnHighlight)
+		}
+	}
+}
 
-		if m.ColumnRainbow {
-			RangeStyle(line.lc, start, end, root.StyleColumnRainbow[c%numC])
+// columnLayoutHighlight applies the style of the column highlight to the laid out line.
+func (root *Root) columnLayoutHighlight(line LineC) {
+	m := root.Doc
+	numC := len(root.StyleColumnRainbow)
+	for c, r := range line.columns {
+		if m.ColumnRainbow && numC > 0 {
+			RangeStyle(line.lc, r[0], r[1], root.StyleColumnRainbow[c%numC])
 		}
 		if c == m.columnCursor {
-			RangeStyle(line.lc, start, end, root.StyleColumnHighlight)
+			RangeStyle(line.lc, r[0], r[1], root.StyleColumnHighlight)
 		}
 	}
 }
@@ -740,7 +740,7 @@ func TestRoot_columnDelimiterHighlight(t *testing.T) {
 			m.columnCursor = tt.fields.columnCursor
 			root.StyleColumnHighlight = OVStyle{Bold: true}
 			line := root.Doc.getLineC(tt.args.lineNum, root.Doc.TabWidth)
-			root.columnDelimiterHighlight(line)
+			root.columnDelimiterHighlight(tt.args.lineNum, line)
 			if line.str != tt.want.str {
 				t.Errorf("\nline: %v\nwant: %v\n", line.str, tt.want.str)
 			}
@@ -829,6 +829,30 @@ func TestRoot_columnWidthHighlight(t *testing.T) {
 	}
 }
 
+func TestRoot_highlightEmptyStyles(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "column.txt"))
+	root.StyleColumnRainbow = nil
+	root.StyleMultiColorHighlight = nil
+	m := root.Doc
+	m.ColumnMode = true
+	m.ColumnRainbow = true
+	m.setDelimiter("|")
+	m.setMultiColorWords([]string{"a"})
+	root.prepareScreen()
+	line := m.getLineC(1, m.TabWidth)
+	// The empty style lists do not panic.
+	root.columnDelimiterHighlight(1, line)
+	root.multiColorHighlight(line)
+	m.ColumnWidth = true
+	m.setColumnWidths(root.scr)
+	root.columnWidthHighlight(line)
+	root.columnLayoutHighlight(line)
+}
+
 func TestRoot_sectionNum(t *testing.T) {
 	tcellNewScreen = fakeScreen
 	defer func() {
@@ -0,0 +1,210 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Preprocessor is a command that converts the file before it is displayed (like LESSOPEN).
+// The file is chosen by Pattern or Magic.
+type Preprocessor struct {
+	// Pattern is the glob pattern of the file name (e.g. "*.pdf").
+	Pattern string
+	// Magic is the bytes at the beginning of the file (e.g. "%PDF-").
+	Magic string
+	// Command is the command and arguments. {file} is replaced by the file name.
+	// If {file} is not included, the file name is added at the end.
+	Command []string
+}
+
+// match returns true if the file matches the preprocessor.
+func (p Preprocessor) match(fileName string, head []byte) bool {
+	if p.Pattern != "" {
+		if ok, _ := filepath.Match(p.Pattern, filepath.Base(fileName)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p.Pattern, fileName); ok {
+			return true
+		}
+	}
+	if p.Magic != "" && bytes.HasPrefix(head, []byte(p.Magic)) {
+		return true
+	}
+	return false
+}
+
+// args returns the command arguments for the file.
+func (p Preprocessor) args(fileName string) []string {
+	args := make([]string, 0, len(p.Command)+1)
+	hasFile := false
+	for _, a := range p.Command {
+		if strings.Contains(a, "{file}") {
+			hasFile = true
+		}
+		args = append(args, strings.ReplaceAll(a, "{file}", fileName))
+	}
+	if !hasFile {
+		args = append(args, fileName)
+	}
+	return args
+}
+
+// magicLen is the length of the head of the file compared with Magic.
+const magicLen = 64
+
+// findPreprocessor returns the preprocessor that matches the file.
+func findPreprocessor(preprocessors []Preprocessor, fileName string, f *os.File) (Preprocessor, bool) {
+	head := make([]byte, magicLen)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		n = 0
+	}
+	head = head[:n]
+	for _, p := range preprocessors {
+		if len(p.Command) == 0 {
+			continue
+		}
+		if p.match(fileName, head) {
+			return p, true
+		}
+	}
+	return Preprocessor{}, false
+}
+
+// preprocessReader returns the output of the preprocessor.
+// It returns false if no preprocessor matches or the preprocessor cannot be started,
+// and then the file is read as it is.
+// The output is read while the preprocessor is running,
+// so that the file is displayed without waiting for the preprocessor to finish.
+// Since it is called every time the file is opened, reload reruns the preprocessor.
+func (m *Document) preprocessReader(f *os.File) (io.Reader, bool) {
+	fileName := m.FileName
+	if len(Preprocessors) == 0 || !m.reopenable || fileName == "" || f == os.Stdin {
+		return nil, false
+	}
+	p, ok := findPreprocessor(Preprocessors, fileName, f)
+	if !ok {
+		return nil, false
+	}
+	args := p.args(fileName)
+	//nolint:gosec
+	cmd := exec.Command(args[0], args[1:]...)
+	out := &preprocessOutput{
+		cmd:      cmd,
+		file:     f,
+		fileName: fileName,
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Printf("preprocess %s: %s", fileName, err)
+		return nil, false
+	}
+	// The stderr is not copied by cmd, so that Wait does not wait for
+	// the child processes of the preprocessor that keep the stderr open.
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		log.Printf("preprocess %s: %s", fileName, err)
+		return nil, false
+	}
+	out.stdout = stdout
+	if err := cmd.Start(); err != nil {
+		log.Printf("preprocess %s: %s", fileName, err)
+		return nil, false
+	}
+	go logPreprocessError(fileName, stderr)
+	log.Printf("preprocess %s: %s", fileName, strings.Join(args, " "))
+	m.preprocess.set(out)
+	return out, true
+}
+
+// stopPreprocess stops the running preprocessor of the document.
+func (m *Document) stopPreprocess() {
+	m.preprocess.set(nil)
+}
+
+// logPreprocessError outputs the stderr of the preprocessor to the log.
+func logPreprocessError(fileName string, stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		log.Printf("preprocess %s: %s", fileName, scanner.Text())
+	}
+}
+
+// preprocessOutput is the output of the running preprocessor.
+type preprocessOutput struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	// file is read as it is if the preprocessor fails without output.
+	file     *os.File
+	fileName string
+	// fallback is the reader of the file after the preprocessor fails.
+	fallback io.Reader
+	// n is the number of bytes read from the preprocessor.
+	n    int64
+	once sync.Once
+	err  error
+}
+
+// Read reads the output of the preprocessor.
+func (p *preprocessOutput) Read(b []byte) (int, error) {
+	if p.fallback != nil {
+		return p.fallback.Read(b)
+	}
+	n, err := p.stdout.Read(b)
+	p.n += int64(n)
+	if err != io.EOF {
+		return n, err
+	}
+	if werr := p.wait(); werr != nil && p.n == 0 {
+		if _, err := p.file.Seek(0, io.SeekStart); err != nil {
+			return n, err
+		}
+		p.fallback = p.file
+		return p.fallback.Read(b)
+	}
+	return n, err
+}
+
+// wait waits for the preprocessor to exit, and logs the error.
+func (p *preprocessOutput) wait() error {
+	p.once.Do(func() {
+		p.err = p.cmd.Wait()
+		if p.err != nil {
+			log.Printf("preprocess %s: %s", p.fileName, p.err)
+		}
+	})
+	return p.err
+}
+
+// kill stops the preprocessor.
+func (p *preprocessOutput) kill() {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		log.Printf("preprocess %s: %s", p.fileName, err)
+	}
+	_ = p.wait()
+}
+
+// runningPreprocess holds the running preprocessor of the document.
+type runningPreprocess struct {
+	mu     sync.Mutex
+	output *preprocessOutput
+}
+
+// set replaces the running preprocessor and stops the previous one.
+func (r *runningPreprocess) set(output *preprocessOutput) {
+	r.mu.Lock()
+	prev := r.output
+	r.output = output
+	r.mu.Unlock()
+	if prev != nil {
+		prev.kill()
+	}
+}
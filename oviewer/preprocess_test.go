@@ -0,0 +1,156 @@
+package oviewer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreprocessor_match(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        Preprocessor
+		fileName string
+		head     []byte
+		want     bool
+	}{
+		{
+			name:     "pattern",
+			p:        Preprocessor{Pattern: "*.pdf"},
+			fileName: "/tmp/test.pdf",
+			want:     true,
+		},
+		{
+			name:     "patternNotMatch",
+			p:        Preprocessor{Pattern: "*.pdf"},
+			fileName: "/tmp/test.txt",
+			want:     false,
+		},
+		{
+			name:     "magic",
+			p:        Preprocessor{Magic: "%PDF-"},
+			fileName: "/tmp/test",
+			head:     []byte("%PDF-1.7"),
+			want:     true,
+		},
+		{
+			name:     "magicNotMatch",
+			p:        Preprocessor{Magic: "PK\x03\x04"},
+			fileName: "/tmp/test",
+			head:     []byte("%PDF-1.7"),
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.match(tt.fileName, tt.head); got != tt.want {
+				t.Errorf("Preprocessor.match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreprocessor_args(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		want    []string
+	}{
+		{
+			name:    "append",
+			command: []string{"pdftotext", "-layout"},
+			want:    []string{"pdftotext", "-layout", "test.pdf"},
+		},
+		{
+			name:    "replace",
+			command: []string{"pdftotext", "{file}", "-"},
+			want:    []string{"pdftotext", "test.pdf", "-"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Preprocessor{Command: tt.command}
+			if got := p.args("test.pdf"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Preprocessor.args() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_preprocessReader(t *testing.T) {
+	tests := []struct {
+		name          string
+		preprocessors []Preprocessor
+		want          string
+	}{
+		{
+			name:          "preprocess",
+			preprocessors: []Preprocessor{{Pattern: "*.txt", Command: []string{"sed", "s/^/X/"}}},
+			want:          "X",
+		},
+		{
+			name:          "fallback",
+			preprocessors: []Preprocessor{{Pattern: "*.txt", Command: []string{"false"}}},
+			want:          "",
+		},
+		{
+			name:          "notMatch",
+			preprocessors: []Preprocessor{{Pattern: "*.pdf", Command: []string{"sed", "s/^/X/"}}},
+			want:          "",
+		},
+	}
+	fileName := filepath.Join(testdata, "normal.txt")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Preprocessors = tt.preprocessors
+			defer func() {
+				Preprocessors = nil
+			}()
+			m, err := OpenDocument(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			eofHelper(t, m)
+			want := tt.want + "khaki	mediumseagreen	steelblue	forestgreen	royalblue	mediumseagreen"
+			if got := m.LineString(0); got != want {
+				t.Errorf("LineString() = %q, want %q", got, want)
+			}
+			if tt.want != "" && m.seekable {
+				t.Errorf("seekable = %v, want %v", m.seekable, false)
+			}
+		})
+	}
+}
+
+func TestDocument_preprocessReaderStream(t *testing.T) {
+	Preprocessors = []Preprocessor{{Pattern: "*.txt", Command: []string{"sh", "-c", "sed 's/^/X/' \"$0\"; sleep 10"}}}
+	defer func() {
+		Preprocessors = nil
+	}()
+	m, err := OpenDocument(filepath.Join(testdata, "normal.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The output is displayed while the preprocessor is running.
+	for i := 0; m.BufEndNum() == 0; i++ {
+		if i >= 100 {
+			t.Fatal("preprocess timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := m.LineString(0); !strings.HasPrefix(got, "Xkhaki") {
+		t.Errorf("LineString() = %q, want prefix %q", got, "Xkhaki")
+	}
+	if m.BufEOF() {
+		t.Errorf("BufEOF() = true while the preprocessor is running")
+	}
+	// Closing the document stops the preprocessor.
+	m.requestClose()
+	m.preprocess.mu.Lock()
+	defer m.preprocess.mu.Unlock()
+	if m.preprocess.output != nil {
+		t.Errorf("preprocessor is still running")
+	}
+}
@@ -2,6 +2,7 @@ package oviewer
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -177,6 +178,47 @@ func (m *Document) seekChunk(reader *bufio.Reader, start int64) error {
 	return nil
 }
 
+// readChunk reads the lines of the chunk from the file without loading it into memory.
+// The position of the file is restored, so that the reader can continue reading.
+func (m *Document) readChunk(chunkNum int) ([][]byte, error) {
+	pos, err := m.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("seek: %w", err)
+	}
+	defer func() {
+		if _, err := m.file.Seek(pos, io.SeekStart); err != nil {
+			log.Printf("readChunk: %s", err)
+		}
+	}()
+
+	chunk := m.store.chunks[chunkNum]
+	if _, err := m.file.Seek(chunk.start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek: %w", err)
+	}
+	_, end := m.store.chunkRange(chunkNum)
+	reader := bufio.NewReader(m.file)
+	lines := make([][]byte, 0, end)
+	var line bytes.Buffer
+	for len(lines) < end {
+		buf, err := reader.ReadSlice('\n')
+		line.Write(buf)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if line.Len() > 0 {
+			lines = append(lines, bytes.Clone(line.Bytes()))
+			line.Reset()
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
 // searchRead searches chunks and loads chunks if found.
 func (m *Document) searchRead(reader *bufio.Reader, chunkNum int, searcher Searcher) (*bufio.Reader, error) {
 	if _, err := m.searchChunk(chunkNum, searcher); err != nil {
@@ -231,6 +273,7 @@ func (m *Document) reloadRead(reader *bufio.Reader) (*bufio.Reader, error) {
 	} else {
 		m.store.loadedChunks.Purge()
 		m.reset()
+		m.csv.reset()
 	}
 
 	return m.reloadFile(reader)
@@ -293,6 +336,16 @@ func (m *Document) fileReader(f *os.File) (io.Reader, error) {
 	atomic.StoreInt32(&m.closed, 0)
 	m.file = f
 
+	if r, ok := m.preprocessReader(f); ok {
+		// The output of the preprocessor cannot be read again from the file.
+		m.seekable = false
+		m.CFormat = UNCOMPRESSED
+		if STDOUTPIPE != nil {
+			r = io.TeeReader(r, STDOUTPIPE)
+		}
+		return r, nil
+	}
+
 	cFormat := UNCOMPRESSED
 	r := io.Reader(m.file)
 	if !SkipExtract {
@@ -364,6 +417,7 @@ func (m *Document) reload() error {
 	}
 
 	atomic.StoreInt32(&m.store.readCancel, 1)
+	m.stopPreprocess()
 	m.requestReload()
 	atomic.StoreInt32(&m.store.readCancel, 0)
 	if !m.WatchMode {
@@ -382,6 +436,7 @@ func (m *Document) reset() {
 	m.store.setNewLoadChunks(m.memoryLimit)
 	atomic.StoreInt32(&m.store.changed, 1)
 	m.ClearCache()
+	m.csv.reset()
 }
 
 // checkClose returns if the file is closed.
@@ -444,7 +499,9 @@ func (m *Document) ReadAll(r io.Reader) error {
 			return
 		}
 
-		if err := m.readAll(reader); err != nil {
+		err := m.readAll(reader)
+		m.scanCSVAll()
+		if err != nil {
 			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed) {
 				m.store.offset = m.store.size
 				atomic.StoreInt32(&m.store.eof, 1)
@@ -466,6 +523,7 @@ func (m *Document) readAll(reader *bufio.Reader) error {
 		if err := m.store.readLines(chunk, reader, start, ChunkSize, true); err != nil {
 			return err
 		}
+		m.scanCSVAll()
 		chunk = NewChunk(m.store.size)
 		m.store.mu.Lock()
 		m.store.chunks = append(m.store.chunks, chunk)
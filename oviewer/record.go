@@ -0,0 +1,185 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// recordSeparator separates the name and the value in the record view.
+const recordSeparator = " : "
+
+// rowStart returns the first line number of the row containing the line.
+// The lines following a line break in a quoted field belong to the previous line's row.
+func (m *Document) rowStart(lN int) int {
+	if m.ColumnWidth || !m.isCSV() {
+		return lN
+	}
+	for lN > m.firstLine() && m.csvLineState(lN).quoted {
+		lN--
+	}
+	return lN
+}
+
+// rowLines returns the lines of the row starting at the line number.
+func (m *Document) rowLines(lN int) ([]string, error) {
+	csv := !m.ColumnWidth && m.isCSV()
+	var lines []string
+	for {
+		str, err := m.LineStr(lN)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, str)
+		lN++
+		if !csv || lN >= m.BufEndNum() || !m.csvLineState(lN).quoted {
+			break
+		}
+	}
+	return lines, nil
+}
+
+// formatRecord formats the values of a row as "name : value" lines.
+// The columns without a name in the header are named by the column number.
+// The lines following a line break in the value are indented.
+func formatRecord(names []string, values []string) string {
+	names = names[:min(len(names), len(values))]
+	for n := len(names); n < len(values); n++ {
+		names = append(names, fmt.Sprintf("col%d", n+1))
+	}
+	width := 0
+	for _, name := range names {
+		width = max(width, runewidth.StringWidth(name))
+	}
+	indent := "\n" + strings.Repeat(" ", width+len(recordSeparator))
+
+	var b strings.Builder
+	for n, value := range values {
+		b.WriteString(runewidth.FillRight(names[n], width))
+		b.WriteString(recordSeparator)
+		b.WriteString(strings.ReplaceAll(value, "\n", indent))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// newRecordDoc returns the record view of the row starting at the line number.
+func newRecordDoc(parent *Document, lN int) (*Document, error) {
+	lines, err := parent.rowLines(lN)
+	if err != nil {
+		return nil, err
+	}
+	values := parent.rowValues(strings.Join(lines, "\n"))
+	str := formatRecord(parent.columnNames(), values)
+
+	m, err := renderDoc(parent, strings.NewReader(str))
+	if err != nil {
+		return nil, err
+	}
+	m.documentType = DocRecord
+	m.FileName = parent.FileName
+	m.Caption = fmt.Sprintf("record:line %d", lN-parent.firstLine()+1)
+	m.general = mergeGeneral(parent.general, m.general)
+	m.ColumnMode = false
+	m.WrapMode = true
+	m.Header = 0
+	m.SkipLines = 0
+	m.SectionDelimiter = ""
+	m.regexpCompile()
+	for n := 0; n < strings.Count(str, "\n"); n++ {
+		m.lineNumMap.Store(n, lN)
+	}
+	return m, nil
+}
+
+// recordLN returns the line number of the row displayed in the record view.
+func (m *Document) recordLN() int {
+	lN, ok := m.lineNumMap.LoadForward(0)
+	if !ok {
+		return m.parent.firstLine()
+	}
+	return lN
+}
+
+// toggleRecordView switches between the record view of the current row and the normal view.
+// The current row is the cursor line in visual mode, otherwise the top line of the body.
+func (root *Root) toggleRecordView(ctx context.Context) {
+	m := root.Doc
+	if m.documentType == DocRecord {
+		root.closeRecordView(ctx)
+		return
+	}
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	lN := m.topLN + m.firstLine()
+	if root.visual.active {
+		lN = root.visual.cursorLN
+	}
+	if lN >= m.BufEndNum() {
+		root.setMessage(ErrNoMoreRecord.Error())
+		return
+	}
+	root.setRecordView(ctx, m, m.rowStart(lN))
+}
+
+// setRecordView displays the record view of the row starting at the line number.
+func (root *Root) setRecordView(ctx context.Context, parent *Document, lN int) {
+	doc, err := newRecordDoc(parent, lN)
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	root.setDocument(ctx, doc)
+	root.setMessage(doc.Caption)
+}
+
+// closeRecordView returns to the document of the record view,
+// and moves to the row displayed in the record view.
+func (root *Root) closeRecordView(ctx context.Context) {
+	m := root.Doc
+	lN := m.recordLN()
+	root.toNormal(ctx)
+	if root.Doc == m.parent {
+		root.Doc.moveLine(lN - root.Doc.firstLine())
+	}
+	root.setMessage("")
+}
+
+// isTop returns true if the top of the document is displayed.
+func (m *Document) isTop() bool {
+	return m.topLN <= 0 && m.topLX == 0
+}
+
+// isBottom returns true if the last line of the document is displayed to the end.
+func (m *Document) isBottom() bool {
+	return m.bottomLN >= m.BufEndNum()
+}
+
+// moveRecord displays the record n rows after (before if negative) the current record.
+func (root *Root) moveRecord(ctx context.Context, n int) {
+	m := root.Doc
+	parent := m.parent
+	lN := m.recordLN()
+	for ; n > 0; n-- {
+		lines, err := parent.rowLines(lN)
+		if err != nil || lN+len(lines) >= parent.BufEndNum() {
+			break
+		}
+		lN += len(lines)
+	}
+	for ; n < 0; n++ {
+		if lN <= parent.firstLine() {
+			break
+		}
+		lN = parent.rowStart(lN - 1)
+	}
+	if n != 0 && lN == m.recordLN() {
+		root.setMessage(ErrNoMoreRecord.Error())
+		return
+	}
+	root.setRecordView(ctx, parent, lN)
+}
@@ -0,0 +1,173 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_formatRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		names  []string
+		values []string
+		want   string
+	}{
+		{
+			name:   "align",
+			names:  []string{"id", "status"},
+			values: []string{"1", "200"},
+			want:   "id     : 1\nstatus : 200\n",
+		},
+		{
+			name:   "noName",
+			names:  []string{"id"},
+			values: []string{"1", "x"},
+			want:   "id   : 1\ncol2 : x\n",
+		},
+		{
+			name:   "lessValues",
+			names:  []string{"id", "status"},
+			values: []string{"1"},
+			want:   "id : 1\n",
+		},
+		{
+			name:   "lineBreak",
+			names:  []string{"id", "memo"},
+			values: []string{"1", "a\nb"},
+			want:   "id   : 1\nmemo : a\n       b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRecord(tt.names, tt.values); got != tt.want {
+				t.Errorf("formatRecord() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_rowLines(t *testing.T) {
+	m := docHelper(t, "id,memo\n1,\"a\nb\"\n2,c\n")
+	m.setDelimiter(",")
+	csvScanHelper(t, m)
+	m.Header = 1
+	if got := m.rowStart(2); got != 1 {
+		t.Errorf("Document.rowStart() = %d, want 1", got)
+	}
+	lines, err := m.rowLines(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1,\"a", "b\""}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("Document.rowLines() = %q, want %q", lines, want)
+	}
+	if got, want := m.rowValues("1,\"a\nb\""), []string{"1", "a\nb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.rowValues() = %q, want %q", got, want)
+	}
+}
+
+func TestRoot_toggleRecordView(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	parent := root.Doc
+	parent.Header = 1
+	parent.ColumnMode = true
+	parent.setDelimiter(",")
+	csvScanHelper(t, parent)
+	ctx := context.Background()
+
+	recordLines := func() []string {
+		t.Helper()
+		m := root.Doc
+		if m.documentType != DocRecord {
+			t.Fatalf("document type = %v, want %v", m.documentType, DocRecord)
+		}
+		for !m.BufEOF() {
+		}
+		lines := make([]string, 0, m.BufEndNum())
+		for n := 0; n < m.BufEndNum(); n++ {
+			lines = append(lines, m.LineString(n))
+		}
+		return lines
+	}
+
+	root.toggleRecordView(ctx)
+	want := []string{
+		"name    : a",
+		"size    : 1K",
+		"latency : 30",
+		"time    : 2024-01-02T00:00:00Z",
+	}
+	if got := recordLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("toggleRecordView() = %q, want %q", got, want)
+	}
+
+	root.moveRecord(ctx, 2)
+	if got := recordLines(); got[0] != "name    : c, quoted" {
+		t.Errorf("moveRecord() = %q, want the third record", got)
+	}
+	root.moveRecord(ctx, 5)
+	if got := recordLines(); got[0] != "name    : d" {
+		t.Errorf("moveRecord() = %q, want the last record", got)
+	}
+	root.moveRecord(ctx, -1)
+	if got := recordLines(); got[0] != "name    : c, quoted" {
+		t.Errorf("moveRecord() = %q, want the third record", got)
+	}
+
+	root.toggleRecordView(ctx)
+	if root.Doc != parent {
+		t.Fatalf("toggleRecordView() did not return to the document")
+	}
+	if parent.topLN != 2 {
+		t.Errorf("toggleRecordView() topLN = %d, want 2", parent.topLN)
+	}
+}
+
+func TestRoot_moveOneRecordView(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	parent := root.Doc
+	parent.Header = 1
+	parent.ColumnMode = true
+	parent.setDelimiter(",")
+	csvScanHelper(t, parent)
+	ctx := context.Background()
+	root.prepareScreen()
+
+	root.toggleRecordView(ctx)
+	m := root.Doc
+	for !m.BufEOF() {
+	}
+	// The record is longer than the screen.
+	m.bottomLN = 2
+	root.moveDownOne(ctx)
+	if root.Doc != m || m.topLN != 1 {
+		t.Fatalf("moveDownOne() topLN = %d, want to scroll the record", m.topLN)
+	}
+	root.moveUpOne(ctx)
+	if root.Doc != m || m.topLN != 0 {
+		t.Fatalf("moveUpOne() topLN = %d, want to scroll the record", m.topLN)
+	}
+	// The bottom of the record is displayed.
+	m.bottomLN = m.BufEndNum()
+	root.moveDownOne(ctx)
+	if root.Doc == m || root.Doc.recordLN() != m.recordLN()+1 {
+		t.Fatalf("moveDownOne() did not move to the next record")
+	}
+	next := root.Doc
+	root.moveUpOne(ctx)
+	if root.Doc == next || root.Doc.recordLN() != m.recordLN() {
+		t.Errorf("moveUpOne() did not move to the previous record")
+	}
+}
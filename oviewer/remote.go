@@ -0,0 +1,229 @@
+package oviewer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// remoteQueryTimeout is the time to wait for the event loop to answer a query.
+const remoteQueryTimeout = 3 * time.Second
+
+// Remote control commands.
+const (
+	remoteSearch      = "search"
+	remoteBackSearch  = "back_search"
+	remoteFilter      = "filter"
+	remoteMoveLine    = "move_line"
+	remoteAddDocument = "add_document"
+	remoteSetDocument = "set_document"
+	remoteReload      = "reload"
+	remoteQuit        = "quit"
+	remoteQuery       = "query"
+)
+
+// remoteRequest is a line of JSON received from the remote control socket.
+type remoteRequest struct {
+	// Command is the name of the command (e.g. "search", "move_line", "query").
+	Command string `json:"command"`
+	// Value is the search or filter pattern.
+	Value string `json:"value,omitempty"`
+	// NonMatch filters the lines that do not match.
+	NonMatch bool `json:"non_match,omitempty"`
+	// Line is the line number for move_line.
+	Line int `json:"line,omitempty"`
+	// Doc is the document number for set_document.
+	Doc int `json:"doc,omitempty"`
+	// File is the file name for add_document.
+	File string `json:"file,omitempty"`
+}
+
+// remoteResponse is a line of JSON returned to the remote control socket.
+type remoteResponse struct {
+	OK    bool         `json:"ok"`
+	Error string       `json:"error,omitempty"`
+	State *remoteState `json:"state,omitempty"`
+}
+
+// remoteState is the state of the current document returned by the query.
+type remoteState struct {
+	// Doc is the number of the current document.
+	Doc int `json:"doc"`
+	// Docs is the number of documents.
+	Docs int `json:"docs"`
+	// FileName is the file name of the current document.
+	FileName string `json:"file_name"`
+	// Caption is the caption of the current document.
+	Caption string `json:"caption,omitempty"`
+	// Line is the line number at the top of the screen (the number passed to move_line).
+	Line int `json:"line"`
+	// BottomLine is the line number at the bottom of the screen.
+	BottomLine int `json:"bottom_line"`
+	// EndNum is the number of lines read.
+	EndNum int `json:"end_num"`
+	// EOF is true if the document has been read to the end.
+	EOF bool `json:"eof"`
+	// Search is the current search word.
+	Search string `json:"search,omitempty"`
+	// SearchLine is the line number of the last match.
+	SearchLine int `json:"search_line,omitempty"`
+}
+
+// Listen starts accepting commands on the Unix domain socket at path.
+// Each line received is a JSON command, and a JSON response is returned for each line.
+//
+//	{"command":"search","value":"error"}
+//	{"command":"query"}
+func (root *Root) Listen(path string) error {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("%w: %s", ErrAlreadyListening, path)
+	}
+	// Remove the socket left by the process that did not exit normally.
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	root.remoteListener = listener
+	go root.acceptRemote(listener)
+	return nil
+}
+
+// closeRemote closes the remote control socket.
+// The socket file is removed when the listener is closed.
+func (root *Root) closeRemote() {
+	if root.remoteListener == nil {
+		return
+	}
+	root.remoteListener.Close()
+	root.remoteListener = nil
+}
+
+// acceptRemote accepts connections until the listener is closed.
+func (root *Root) acceptRemote(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("remote: %s", err)
+			}
+			return
+		}
+		go root.serveRemote(conn)
+	}
+}
+
+// serveRemote processes the commands of the connection line by line.
+func (root *Root) serveRemote(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req remoteRequest
+		var res remoteResponse
+		if err := json.Unmarshal(line, &req); err != nil {
+			res = remoteResponse{Error: err.Error()}
+		} else {
+			res = root.remoteCommand(req)
+		}
+		if err := encoder.Encode(res); err != nil {
+			return
+		}
+	}
+}
+
+// remoteCommand runs the command.
+// The commands are passed to the event loop by the public API, which fires events.
+func (root *Root) remoteCommand(req remoteRequest) remoteResponse {
+	switch req.Command {
+	case remoteSearch:
+		root.Search(req.Value)
+	case remoteBackSearch:
+		root.BackSearch(req.Value)
+	case remoteFilter:
+		root.Filter(req.Value, req.NonMatch)
+	case remoteMoveLine:
+		root.MoveLine(req.Line)
+	case remoteAddDocument:
+		m, err := OpenDocument(req.File)
+		if err != nil {
+			return remoteResponse{Error: err.Error()}
+		}
+		root.AddDocument(m)
+	case remoteSetDocument:
+		if req.Doc < 0 || req.Doc >= root.DocumentLen() {
+			return remoteResponse{Error: fmt.Sprintf("%s: %d", ErrOutOfRange, req.Doc)}
+		}
+		root.SetDocument(req.Doc)
+	case remoteReload:
+		root.Reload(context.Background())
+	case remoteQuit:
+		root.Quit(context.Background())
+	case remoteQuery:
+		state, err := root.queryRemote()
+		if err != nil {
+			return remoteResponse{Error: err.Error()}
+		}
+		return remoteResponse{OK: true, State: state}
+	default:
+		return remoteResponse{Error: fmt.Sprintf("%s: %s", ErrUnknownCommand, req.Command)}
+	}
+	return remoteResponse{OK: true}
+}
+
+// eventRemoteQuery represents a query from the remote control socket.
+type eventRemoteQuery struct {
+	tcell.EventTime
+	reply chan remoteState
+}
+
+// queryRemote asks the event loop for the state,
+// so that the state is read after the commands sent before.
+func (root *Root) queryRemote() (*remoteState, error) {
+	ev := &eventRemoteQuery{reply: make(chan remoteState, 1)}
+	ev.SetEventNow()
+	root.postEvent(ev)
+	select {
+	case state := <-ev.reply:
+		return &state, nil
+	case <-time.After(remoteQueryTimeout):
+		return nil, ErrRemoteTimeout
+	}
+}
+
+// remoteState returns the state of the current document.
+func (root *Root) remoteState() remoteState {
+	m := root.Doc
+	state := remoteState{
+		Doc:        root.CurrentDoc,
+		Docs:       root.DocumentLen(),
+		FileName:   m.FileName,
+		Caption:    m.Caption,
+		Line:       m.topLN + 1,
+		BottomLine: m.bottomLN,
+		EndNum:     m.BufEndNum(),
+		EOF:        m.BufEOF(),
+	}
+	if root.searcher != nil {
+		state.Search = root.searcher.String()
+	}
+	if m.lastSearchLN >= 0 {
+		state.SearchLine = m.lastSearchLN + 1
+	}
+	return state
+}
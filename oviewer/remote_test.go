@@ -0,0 +1,112 @@
+package oviewer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_remoteCommand(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	tests := []struct {
+		name string
+		req  remoteRequest
+		want bool
+	}{
+		{
+			name: "search",
+			req:  remoteRequest{Command: remoteSearch, Value: "test"},
+			want: true,
+		},
+		{
+			name: "moveLine",
+			req:  remoteRequest{Command: remoteMoveLine, Line: 10},
+			want: true,
+		},
+		{
+			name: "setDocumentOutOfRange",
+			req:  remoteRequest{Command: remoteSetDocument, Doc: 1},
+			want: false,
+		},
+		{
+			name: "addDocumentNotFound",
+			req:  remoteRequest{Command: remoteAddDocument, File: filepath.Join(testdata, "notfound")},
+			want: false,
+		},
+		{
+			name: "unknown",
+			req:  remoteRequest{Command: "unknown"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootHelper(t)
+			got := root.remoteCommand(tt.req)
+			if got.OK != tt.want {
+				t.Errorf("Root.remoteCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_Listen(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "normal.txt"))
+	path := filepath.Join(t.TempDir(), "ov.sock")
+	if err := root.Listen(path); err != nil {
+		t.Fatal(err)
+	}
+	defer root.closeRemote()
+	if err := root.Listen(path); err == nil {
+		t.Errorf("Root.Listen() error = nil, want %v", ErrAlreadyListening)
+	}
+
+	// Answer the query in place of the event loop.
+	ctx := context.Background()
+	go func() {
+		for {
+			ev := root.Screen.PollEvent()
+			if ev == nil {
+				return
+			}
+			if ev, ok := ev.(*eventRemoteQuery); ok {
+				root.event(ctx, ev)
+			}
+		}
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(`{"command":"query"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(conn)
+	if !scanner.Scan() {
+		t.Fatal(scanner.Err())
+	}
+	var res remoteResponse
+	if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.OK || res.State == nil {
+		t.Fatalf("response = %v", res)
+	}
+	if res.State.Docs != 1 || res.State.EndNum != root.Doc.BufEndNum() {
+		t.Errorf("state = %v", res.State)
+	}
+}
@@ -24,28 +24,68 @@ const (
 // saveBuffer saves the buffer to the specified file.
 func (root *Root) saveBuffer(input string) {
 	fileName := strings.TrimSpace(input)
+	file, ok := root.openSaveFile(fileName)
+	if !ok {
+		return
+	}
+	defer file.Close()
 
-	flag, err := root.saveFlag(fileName)
-	if err != nil {
-		root.setMessage("save cancel")
+	if root.visual.active {
+		str, err := root.visualString()
+		root.endVisual()
+		if err != nil {
+			root.setMessageLogf("cannot save: %s:%s", fileName, err)
+			return
+		}
+		if _, err := file.WriteString(str); err != nil {
+			root.setMessageLogf("cannot save: %s:%s", fileName, err)
+			return
+		}
+		root.setMessageLogf("saved %s", fileName)
 		return
 	}
-	perm := os.FileMode(0o644)
-	file, err := os.OpenFile(fileName, flag, perm)
-	if err != nil {
+
+	if err := root.Doc.Export(file, root.Doc.BufStartNum(), root.Doc.BufEndNum()); err != nil {
 		root.setMessageLogf("cannot save: %s:%s", fileName, err)
 		return
 	}
+
+	root.setMessageLogf("saved %s", fileName)
+}
+
+// saveString saves the string to the specified file.
+func (root *Root) saveString(input string, str string) {
+	fileName := strings.TrimSpace(input)
+	file, ok := root.openSaveFile(fileName)
+	if !ok {
+		return
+	}
 	defer file.Close()
 
-	if err := root.Doc.Export(file, root.Doc.BufStartNum(), root.Doc.BufEndNum()); err != nil {
+	if _, err := file.WriteString(str); err != nil {
 		root.setMessageLogf("cannot save: %s:%s", fileName, err)
 		return
 	}
-
 	root.setMessageLogf("saved %s", fileName)
 }
 
+// openSaveFile opens the file to save after confirming to overwrite or append.
+// It returns false if canceled or failed.
+func (root *Root) openSaveFile(fileName string) (*os.File, bool) {
+	flag, err := root.saveFlag(fileName)
+	if err != nil {
+		root.setMessage("save cancel")
+		return nil, false
+	}
+	perm := os.FileMode(0o644)
+	file, err := os.OpenFile(fileName, flag, perm)
+	if err != nil {
+		root.setMessageLogf("cannot save: %s:%s", fileName, err)
+		return nil, false
+	}
+	return file, true
+}
+
 func (root *Root) saveFlag(fileName string) (int, error) {
 	flag := os.O_WRONLY | os.O_CREATE
 	_, err := os.Stat(fileName)
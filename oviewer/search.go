@@ -242,7 +242,13 @@ func (root *Root) setSearcher(word string, caseSensitive bool) Searcher {
 		return nil
 	}
 	root.input.value = word
+	searcher := root.newSearcher(word, caseSensitive)
+	root.searcher = searcher
+	return searcher
+}
 
+// newSearcher returns the Searcher of the word with the search options.
+func (root *Root) newSearcher(word string, caseSensitive bool) Searcher {
 	if root.Config.SmartCaseSensitive {
 		for _, ch := range word {
 			if unicode.IsUpper(ch) {
@@ -252,13 +258,16 @@ func (root *Root) setSearcher(word string, caseSensitive bool) Searcher {
 		}
 	}
 	reg := regexpCompile(word, caseSensitive)
-	searcher := NewSearcher(word, reg, caseSensitive, root.Config.RegexpSearch)
-	root.searcher = searcher
-	return searcher
+	return NewSearcher(word, reg, caseSensitive, root.Config.RegexpSearch)
 }
 
 // searchMove searches forward/backward and moves to the nearest matching line.
 func (root *Root) searchMove(ctx context.Context, forward bool, lineNum int, searcher Searcher) bool {
+	return root.searchMoveCount(ctx, forward, lineNum, 1, searcher)
+}
+
+// searchMoveCount searches for the count-th matching line and moves to it.
+func (root *Root) searchMoveCount(ctx context.Context, forward bool, lineNum int, count int, searcher Searcher) bool {
 	if searcher == nil {
 		return false
 	}
@@ -273,7 +282,7 @@ func (root *Root) searchMove(ctx context.Context, forward bool, lineNum int, sea
 	})
 
 	eg.Go(func() error {
-		n, err := root.Doc.searchLine(ctx, searcher, forward, lineNum)
+		n, err := root.Doc.searchLineCount(ctx, searcher, forward, lineNum, count)
 		root.sendSearchQuit()
 		if err != nil {
 			return fmt.Errorf("search:%w:%v", err, word)
@@ -298,6 +307,24 @@ func (m *Document) searchLine(ctx context.Context, searcher Searcher, forward bo
 	return m.BackSearchLine(ctx, searcher, lineNum)
 }
 
+// searchLineCount searches for the count-th matching line from lineNum.
+func (m *Document) searchLineCount(ctx context.Context, searcher Searcher, forward bool, lineNum int, count int) (int, error) {
+	n, err := m.searchLine(ctx, searcher, forward, lineNum)
+	for i := 1; i < count && err == nil; i++ {
+		next := n + 1
+		if !forward {
+			next = n - 1
+		}
+		nn, nerr := m.searchLine(ctx, searcher, forward, next)
+		if nerr != nil {
+			// Stop at the last match found.
+			break
+		}
+		n = nn
+	}
+	return n, err
+}
+
 // Search searches for the search term and moves to the nearest matching line.
 func (m *Document) Search(ctx context.Context, searcher Searcher, chunkNum int, lineNum int) (int, error) {
 	if !m.seekable {
@@ -642,10 +669,25 @@ func (root *Root) backSearch(ctx context.Context, str string, next int) {
 	root.searchMove(ctx, false, root.startSearchLN()+next, searcher)
 }
 
+// nextSearch repeats the search count times in the specified direction.
+func (root *Root) nextSearch(ctx context.Context, str string, forward bool, count int) {
+	// The column search continues in the column.
+	searcher := root.searcher
+	if _, ok := searcher.(*columnSearcher); !ok || searcher.String() != str {
+		searcher = root.setSearcher(str, root.Config.CaseSensitive)
+	}
+	next := 1
+	if !forward {
+		next = -1
+	}
+	root.searchMoveCount(ctx, forward, root.startSearchLN()+next, max(count, 1), searcher)
+}
+
 // eventNextSearch represents search event.
 type eventNextSearch struct {
 	tcell.EventTime
-	str string
+	str   string
+	count int
 }
 
 // sendNextSearch fires the eventNextSearch event.
@@ -656,6 +698,7 @@ func (root *Root) sendNextSearch(context.Context) {
 
 	ev := &eventNextSearch{}
 	ev.str = root.searcher.String()
+	ev.count = root.repeatCount()
 	ev.SetEventNow()
 	root.postEvent(ev)
 }
@@ -663,7 +706,8 @@ func (root *Root) sendNextSearch(context.Context) {
 // eventNextBackSearch represents backward search event.
 type eventNextBackSearch struct {
 	tcell.EventTime
-	str string
+	str   string
+	count int
 }
 
 // sendNextBackSearch fires the eventNextBackSearch event.
@@ -674,6 +718,7 @@ func (root *Root) sendNextBackSearch(context.Context) {
 
 	ev := &eventNextBackSearch{}
 	ev.str = root.searcher.String()
+	ev.count = root.repeatCount()
 	ev.SetEventNow()
 	root.postEvent(ev)
 }
@@ -0,0 +1,541 @@
+package oviewer
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortRunLines is the number of rows sorted in memory.
+// More rows are sorted in temporary files and merged (external merge sort).
+var sortRunLines = 100000
+
+// sortType is the comparison of the sort.
+type sortType int
+
+const (
+	sortLexical sortType = iota
+	sortNumeric
+	sortSize
+	sortTime
+)
+
+// sortTypeNames are the names of the sort types.
+var sortTypeNames = []string{
+	sortLexical: "lexical",
+	sortNumeric: "numeric",
+	sortSize:    "size",
+	sortTime:    "time",
+}
+
+// sortTimeLayouts are the layouts of the timestamps that can be sorted.
+var sortTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006/01/02 15:04:05.999999999",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+	time.ANSIC,
+	time.StampNano,
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// sortOption is the column sort order.
+type sortOption struct {
+	typ  sortType
+	desc bool
+}
+
+// parseSortOption parses the sort order such as "numeric" and "-size".
+// The leading "-" means descending. The empty string is lexical.
+func parseSortOption(str string) (sortOption, error) {
+	str = strings.TrimSpace(str)
+	var opt sortOption
+	if strings.HasPrefix(str, "-") {
+		opt.desc = true
+		str = str[1:]
+	}
+	if str == "" {
+		return opt, nil
+	}
+	for t, name := range sortTypeNames {
+		if strings.EqualFold(str, name) {
+			opt.typ = sortType(t)
+			return opt, nil
+		}
+	}
+	return opt, fmt.Errorf("%w: %s", ErrUnknownSortType, str)
+}
+
+// String returns the sort order.
+func (opt sortOption) String() string {
+	if opt.desc {
+		return "-" + sortTypeNames[opt.typ]
+	}
+	return sortTypeNames[opt.typ]
+}
+
+// sortKey is the value of the column to compare.
+type sortKey struct {
+	str string
+	num float64
+	// valid is true if the value is converted to num.
+	valid bool
+}
+
+// newSortKey converts the value of the column for the sort type.
+func newSortKey(typ sortType, str string) sortKey {
+	key := sortKey{str: str}
+	switch typ {
+	case sortNumeric:
+		key.num, key.valid = parseSortNumber(str)
+	case sortSize:
+		key.num, key.valid = parseHumanSize(str)
+	case sortTime:
+		key.num, key.valid = parseSortTime(str)
+	}
+	return key
+}
+
+// compare compares the keys in the sort order.
+// The values that cannot be converted are placed last in both orders.
+func (opt sortOption) compare(a sortKey, b sortKey) int {
+	var c int
+	if opt.typ == sortLexical {
+		c = strings.Compare(a.str, b.str)
+	} else {
+		switch {
+		case a.valid && b.valid:
+			c = cmp.Compare(a.num, b.num)
+		case a.valid:
+			return -1
+		case b.valid:
+			return 1
+		default:
+			return strings.Compare(a.str, b.str)
+		}
+	}
+	if opt.desc {
+		return -c
+	}
+	return c
+}
+
+// parseSortNumber parses the number that may contain the thousands separators.
+func parseSortNumber(str string) (float64, bool) {
+	str = strings.ReplaceAll(str, ",", "")
+	n, err := strconv.ParseFloat(str, 64)
+	return n, err == nil
+}
+
+// parseHumanSize parses the human-readable size such as "512", "1.5K", "10MB" and "2GiB".
+func parseHumanSize(str string) (float64, bool) {
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.' || r == '-' || r == '+')
+	})
+	if i < 0 {
+		i = len(str)
+	}
+	n, err := strconv.ParseFloat(str[:i], 64)
+	if err != nil {
+		return 0, false
+	}
+	unit := strings.ToLower(strings.TrimSpace(str[i:]))
+	unit = strings.TrimSuffix(unit, "b")
+	unit = strings.TrimSuffix(unit, "i")
+	p := strings.Index("kmgtpe", unit)
+	switch {
+	case unit == "":
+		return n, true
+	case len(unit) == 1 && p >= 0:
+		for ; p >= 0; p-- {
+			n *= 1024
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+// parseSortTime parses the timestamp in the sortTimeLayouts.
+func parseSortTime(str string) (float64, bool) {
+	for _, layout := range sortTimeLayouts {
+		t, err := time.Parse(layout, str)
+		if err == nil {
+			return float64(t.UnixNano()), true
+		}
+	}
+	return 0, false
+}
+
+// sortRow is a row to sort.
+// A row has multiple lines if the CSV record has a line break in the quoted field.
+type sortRow struct {
+	key   sortKey
+	lines [][]byte
+	// lN is the line number of the first line in the original document.
+	lN int
+}
+
+// rowSorter sorts the rows in memory and in temporary files.
+type rowSorter struct {
+	opt  sortOption
+	rows []sortRow
+	// runs are the temporary files of the sorted rows.
+	runs []string
+}
+
+// newRowSorter returns a rowSorter.
+func newRowSorter(opt sortOption) *rowSorter {
+	return &rowSorter{opt: opt}
+}
+
+// compare compares the rows, and the rows with the same key keep the original order.
+func (s *rowSorter) compare(a sortRow, b sortRow) int {
+	if c := s.opt.compare(a.key, b.key); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.lN, b.lN)
+}
+
+// add adds a row.
+// If the rows in memory exceed sortRunLines, they are sorted and written to a temporary file.
+func (s *rowSorter) add(row sortRow) error {
+	s.rows = append(s.rows, row)
+	if len(s.rows) < sortRunLines {
+		return nil
+	}
+	return s.flush()
+}
+
+// flush sorts the rows in memory and writes them to a temporary file.
+func (s *rowSorter) flush() error {
+	slices.SortFunc(s.rows, s.compare)
+	f, err := os.CreateTemp("", "ov-sort-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f.Name())
+	w := bufio.NewWriter(f)
+	for _, row := range s.rows {
+		if err := writeSortRow(w, row); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	s.rows = s.rows[:0]
+	return f.Close()
+}
+
+// finish sorts the rows in memory.
+func (s *rowSorter) finish() {
+	slices.SortFunc(s.rows, s.compare)
+}
+
+// close removes the temporary files.
+func (s *rowSorter) close() {
+	for _, name := range s.runs {
+		if err := os.Remove(name); err != nil {
+			log.Println(err)
+		}
+	}
+	s.runs = nil
+}
+
+// each calls fn for the sorted rows.
+// The rows in the temporary files and in memory are merged.
+func (s *rowSorter) each(fn func(row sortRow) error) error {
+	if len(s.runs) == 0 {
+		for _, row := range s.rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	h := &sortHeap{sorter: s}
+	for _, name := range s.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := h.push(&sortRunSource{r: bufio.NewReader(f), typ: s.opt.typ}); err != nil {
+			return err
+		}
+	}
+	if err := h.push(&sortMemSource{rows: s.rows}); err != nil {
+		return err
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		item := h.sources[0]
+		if err := fn(item.row); err != nil {
+			return err
+		}
+		ok, err := item.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
+
+// writeSortRow writes the row to the temporary file.
+func writeSortRow(w *bufio.Writer, row sortRow) error {
+	buf := binary.AppendUvarint(nil, uint64(row.lN))
+	buf = binary.AppendUvarint(buf, uint64(len(row.key.str)))
+	buf = append(buf, row.key.str...)
+	buf = binary.AppendUvarint(buf, uint64(len(row.lines)))
+	for _, line := range row.lines {
+		buf = binary.AppendUvarint(buf, uint64(len(line)))
+		buf = append(buf, line...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readSortRow reads the row from the temporary file.
+func readSortRow(r *bufio.Reader, typ sortType) (sortRow, error) {
+	var row sortRow
+	lN, err := binary.ReadUvarint(r)
+	if err != nil {
+		return row, err
+	}
+	row.lN = int(lN)
+	key, err := readSortBytes(r)
+	if err != nil {
+		return row, err
+	}
+	row.key = newSortKey(typ, string(key))
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return row, err
+	}
+	row.lines = make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		line, err := readSortBytes(r)
+		if err != nil {
+			return row, err
+		}
+		row.lines = append(row.lines, line)
+	}
+	return row, nil
+}
+
+// readSortBytes reads the length and the bytes.
+func readSortBytes(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// sortSource is the sorted rows to merge.
+type sortSource interface {
+	// next returns the next row, or false at the end.
+	next() (sortRow, bool, error)
+}
+
+// sortRunSource is the rows in the temporary file.
+type sortRunSource struct {
+	r   *bufio.Reader
+	typ sortType
+}
+
+func (src *sortRunSource) next() (sortRow, bool, error) {
+	row, err := readSortRow(src.r, src.typ)
+	if err == io.EOF {
+		return row, false, nil
+	}
+	return row, err == nil, err
+}
+
+// sortMemSource is the rows in memory.
+type sortMemSource struct {
+	rows []sortRow
+}
+
+func (src *sortMemSource) next() (sortRow, bool, error) {
+	if len(src.rows) == 0 {
+		return sortRow{}, false, nil
+	}
+	row := src.rows[0]
+	src.rows = src.rows[1:]
+	return row, true, nil
+}
+
+// sortHeapItem is the source with the current row.
+type sortHeapItem struct {
+	src sortSource
+	row sortRow
+}
+
+// next reads the next row of the source.
+func (item *sortHeapItem) next() (bool, error) {
+	row, ok, err := item.src.next()
+	if ok {
+		item.row = row
+	}
+	return ok, err
+}
+
+// sortHeap is the heap of the sources to merge the sorted rows.
+type sortHeap struct {
+	sorter  *rowSorter
+	sources []*sortHeapItem
+}
+
+// push adds the source if it has a row.
+func (h *sortHeap) push(src sortSource) error {
+	item := &sortHeapItem{src: src}
+	ok, err := item.next()
+	if err != nil {
+		return err
+	}
+	if ok {
+		h.sources = append(h.sources, item)
+	}
+	return nil
+}
+
+func (h *sortHeap) Len() int { return len(h.sources) }
+
+func (h *sortHeap) Less(i, j int) bool {
+	return h.sorter.compare(h.sources[i].row, h.sources[j].row) < 0
+}
+
+func (h *sortHeap) Swap(i, j int) { h.sources[i], h.sources[j] = h.sources[j], h.sources[i] }
+
+func (h *sortHeap) Push(x any) { h.sources = append(h.sources, x.(*sortHeapItem)) }
+
+func (h *sortHeap) Pop() any {
+	old := h.sources
+	n := len(old)
+	item := old[n-1]
+	h.sources = old[:n-1]
+	return item
+}
+
+// sortRows reads the rows after the header and sorts them by the column.
+func (m *Document) sortRows(ctx context.Context, column int, opt sortOption) (*rowSorter, error) {
+	sorter := newRowSorter(opt)
+	err := m.columnRows(ctx, column, func(lN int, lines [][]byte, value string, _ bool) error {
+		row := sortRow{lN: lN}
+		for _, line := range lines {
+			row.lines = append(row.lines, bytes.Clone(line))
+		}
+		row.key = newSortKey(opt.typ, value)
+		return sorter.add(row)
+	})
+	if err != nil {
+		sorter.close()
+		return nil, err
+	}
+	sorter.finish()
+	return sorter, nil
+}
+
+// sortColumn sorts the rows by the column of the cursor in the background,
+// and opens the result as a new document when it finishes.
+func (root *Root) sortColumn(ctx context.Context, input string) {
+	opt, err := parseSortOption(input)
+	if err != nil {
+		root.setMessage(err.Error())
+		return
+	}
+	m := root.Doc
+	column := m.cursorColumn()
+	match := fmt.Sprintf("col%d:%s", column+1, opt)
+	root.setMessagef("sort:%s (%v)Cancel", match, strings.Join(root.cancelKeys, ","))
+
+	root.runJob(ctx, "sort", func(ctx context.Context) (func(context.Context), error) {
+		sorter, err := m.sortRows(ctx, column, opt)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) {
+			root.addSortDocument(ctx, m, match, sorter)
+		}, nil
+	})
+}
+
+// addSortDocument opens the sorted rows as a new document.
+func (root *Root) addSortDocument(ctx context.Context, m *Document, match string, sorter *rowSorter) {
+	r, w := io.Pipe()
+	render, err := renderDoc(m, r)
+	if err != nil {
+		sorter.close()
+		log.Println(err)
+		return
+	}
+	render.documentType = DocSort
+	render.Caption = fmt.Sprintf("sort:%s", match)
+	root.addDocument(ctx, render)
+	render.general = mergeGeneral(m.general, render.general)
+	render.Header = m.Header
+	render.SkipLines = m.SkipLines
+	render.columnCursor = m.columnCursor
+
+	go m.sortWriter(render, w, sorter)
+	root.setMessagef("sort:%s", match)
+}
+
+// sortWriter writes the header and the sorted rows to the sorted document.
+// The line numbers of the original document are stored in lineNumMap.
+func (m *Document) sortWriter(render *Document, w io.WriteCloser, sorter *rowSorter) {
+	defer w.Close()
+	defer sorter.close()
+
+	renderLN := render.firstLine()
+	err := m.eachLine(context.Background(), 0, renderLN, func(lN int, line []byte) error {
+		render.lineNumMap.Store(lN, lN)
+		writeLine(w, line)
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	err = sorter.each(func(row sortRow) error {
+		for i, line := range row.lines {
+			render.lineNumMap.Store(renderLN, row.lN+i)
+			writeLine(w, line)
+			renderLN++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("sort: %s", err)
+	}
+}
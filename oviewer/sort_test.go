@@ -0,0 +1,224 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_parseSortOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    sortOption
+		wantErr bool
+	}{
+		{name: "empty", str: "", want: sortOption{typ: sortLexical}},
+		{name: "numeric", str: "numeric", want: sortOption{typ: sortNumeric}},
+		{name: "descSize", str: "-size", want: sortOption{typ: sortSize, desc: true}},
+		{name: "desc", str: "-", want: sortOption{typ: sortLexical, desc: true}},
+		{name: "upper", str: "Time", want: sortOption{typ: sortTime}},
+		{name: "unknown", str: "random", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSortOption(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSortOption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseSortOption() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseHumanSize(t *testing.T) {
+	tests := []struct {
+		str    string
+		want   float64
+		wantOk bool
+	}{
+		{str: "512", want: 512, wantOk: true},
+		{str: "1.5K", want: 1536, wantOk: true},
+		{str: "10MB", want: 10 * 1024 * 1024, wantOk: true},
+		{str: "2GiB", want: 2 * 1024 * 1024 * 1024, wantOk: true},
+		{str: "3 kb", want: 3072, wantOk: true},
+		{str: "100B", want: 100, wantOk: true},
+		{str: "abc", wantOk: false},
+		{str: "1X", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, ok := parseHumanSize(tt.str)
+			if ok != tt.wantOk {
+				t.Fatalf("parseHumanSize() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseHumanSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sortOption_compare(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  sortOption
+		a    string
+		b    string
+		want int
+	}{
+		{name: "lexical", opt: sortOption{typ: sortLexical}, a: "10", b: "9", want: -1},
+		{name: "numeric", opt: sortOption{typ: sortNumeric}, a: "10", b: "9", want: 1},
+		{name: "numericDesc", opt: sortOption{typ: sortNumeric, desc: true}, a: "10", b: "9", want: -1},
+		{name: "numericComma", opt: sortOption{typ: sortNumeric}, a: "1,000", b: "999", want: 1},
+		{name: "invalidLast", opt: sortOption{typ: sortNumeric}, a: "", b: "9", want: 1},
+		{name: "invalidLastDesc", opt: sortOption{typ: sortNumeric, desc: true}, a: "", b: "9", want: 1},
+		{name: "size", opt: sortOption{typ: sortSize}, a: "2K", b: "1M", want: -1},
+		{name: "time", opt: sortOption{typ: sortTime}, a: "2024-01-02T00:00:00Z", b: "2023-12-31 23:59:59", want: 1},
+		{name: "accessLog", opt: sortOption{typ: sortTime}, a: "10/Oct/2000:13:55:36 -0700", b: "10/Oct/2000:13:55:35 -0700", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newSortKey(tt.opt.typ, tt.a)
+			b := newSortKey(tt.opt.typ, tt.b)
+			if got := tt.opt.compare(a, b); got != tt.want {
+				t.Errorf("sortOption.compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_sortRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		runSize int
+		column  int
+		str     string
+		want    []int
+	}{
+		{
+			name:    "inMemory",
+			runSize: 100,
+			column:  1,
+			str:     "h,v\na,3\nb,1\nc,2\nd,1\n",
+			want:    []int{2, 4, 3, 1},
+		},
+		{
+			name:    "external",
+			runSize: 2,
+			column:  1,
+			str:     "h,v\na,3\nb,1\nc,2\nd,1\ne,0\n",
+			want:    []int{5, 2, 4, 3, 1},
+		},
+		{
+			name:    "multiLine",
+			runSize: 100,
+			column:  1,
+			str:     "h,v\n\"a\nb\",2\nc,1\n",
+			want:    []int{3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(n int) { sortRunLines = n }(sortRunLines)
+			sortRunLines = tt.runSize
+			m := docHelper(t, tt.str)
+			m.setDelimiter(",")
+			m.Header = 1
+			sorter, err := m.sortRows(context.Background(), tt.column, sortOption{typ: sortNumeric})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sorter.close()
+			var got []int
+			if err := sorter.each(func(row sortRow) error {
+				got = append(got, row.lN)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.sortRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_sortRowsLarge(t *testing.T) {
+	// The rows after the first chunk are read without loading the chunks.
+	var b strings.Builder
+	b.WriteString("id,v\n")
+	num := ChunkSize + 100
+	for i := 1; i < num; i++ {
+		fmt.Fprintf(&b, "%d,%d\n", i, num-i)
+	}
+	fileName := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := docFileReadHelper(t, fileName)
+	m.setDelimiter(",")
+	m.Header = 1
+	sorter, err := m.sortRows(context.Background(), 1, sortOption{typ: sortNumeric})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sorter.close()
+	var got []int
+	if err := sorter.each(func(row sortRow) error {
+		got = append(got, row.lN)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != num-1 || got[0] != num-1 || got[len(got)-1] != 1 {
+		t.Errorf("Document.sortRows() = %d rows %v...%v, want %d rows", len(got), got[:1], got[len(got)-1:], num-1)
+	}
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Errorf("chunk 1 is loaded")
+	}
+}
+
+func TestRoot_sortColumn(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	root.Doc.Header = 1
+	root.Doc.ColumnMode = true
+	root.Doc.setDelimiter(",")
+	csvScanHelper(t, root.Doc)
+	root.Doc.columnCursor = 2
+	root.sortColumn(context.Background(), "-numeric")
+	jobHelper(t, root)
+	if root.DocumentLen() != 2 {
+		t.Fatalf("sortColumn() documents = %d, want 2", root.DocumentLen())
+	}
+	sorted := root.Doc
+	eofHelper(t, sorted)
+	wantLines := []string{
+		"name,size,latency,time",
+		`"c, quoted",2M,100,2023-12-31T00:00:00Z`,
+		"a,1K,30,2024-01-02T00:00:00Z",
+		"b,512,5,2024-01-01T00:00:00Z",
+		"d,10,,2024-01-03T00:00:00Z",
+	}
+	wantNums := []int{0, 3, 1, 2, 4}
+	for i, want := range wantLines {
+		if got := sorted.LineString(i); got != want {
+			t.Errorf("sortColumn() line %d = %q, want %q", i, got, want)
+		}
+		if n, _ := sorted.lineNumMap.LoadForward(i); n != wantNums[i] {
+			t.Errorf("sortColumn() line number %d = %d, want %d", i, n, wantNums[i])
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package oviewer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// statsTopN is the number of the most frequent values in the statistics.
+const statsTopN = 10
+
+// statsDistinctLimit is the maximum number of distinct values to count.
+// The values after the limit are not counted as distinct values.
+var statsDistinctLimit = 1000000
+
+// statsPercentiles is the percentiles in the statistics.
+var statsPercentiles = []float64{50, 90, 95, 99}
+
+// columnStats is the statistics of a column.
+type columnStats struct {
+	// freq is the number of each value.
+	freq map[string]int
+	// numbers is the numeric values.
+	numbers []float64
+	// rows is the number of rows.
+	rows int
+	// values is the number of non-empty values.
+	values int
+	// empty is the number of rows with an empty or no value.
+	empty int
+	// overflow is true if the distinct values exceed statsDistinctLimit.
+	overflow bool
+	sum      float64
+}
+
+// newColumnStats returns the empty statistics.
+func newColumnStats() *columnStats {
+	return &columnStats{
+		freq: make(map[string]int),
+	}
+}
+
+// add adds the value of a row.
+func (s *columnStats) add(value string, found bool) {
+	s.rows++
+	if !found || value == "" {
+		s.empty++
+		return
+	}
+	s.values++
+	if _, ok := s.freq[value]; ok || len(s.freq) < statsDistinctLimit {
+		s.freq[value]++
+	} else {
+		s.overflow = true
+	}
+	if n, ok := parseSortNumber(value); ok && !math.IsNaN(n) && !math.IsInf(n, 0) {
+		s.numbers = append(s.numbers, n)
+		s.sum += n
+	}
+}
+
+// percentile returns the percentile p of the sorted numbers (nearest-rank method).
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+// statsValue is a value and its number.
+type statsValue struct {
+	value string
+	count int
+}
+
+// top returns the n most frequent values.
+// The values with the same number are in lexical order.
+func (s *columnStats) top(n int) []statsValue {
+	values := make([]statsValue, 0, len(s.freq))
+	for v, c := range s.freq {
+		values = append(values, statsValue{value: v, count: c})
+	}
+	slices.SortFunc(values, func(a, b statsValue) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.value, b.value)
+	})
+	return values[:min(n, len(values))]
+}
+
+// formatStat formats the number of the statistics.
+func formatStat(n float64) string {
+	return strconv.FormatFloat(math.Round(n*1e6)/1e6, 'f', -1, 64)
+}
+
+// String returns the statistics as a text.
+func (s *columnStats) String() string {
+	var b strings.Builder
+	distinct := strconv.Itoa(len(s.freq))
+	if s.overflow {
+		distinct = ">" + distinct
+	}
+	fmt.Fprintf(&b, "%-10s %d\n", "Rows", s.rows)
+	fmt.Fprintf(&b, "%-10s %d\n", "Values", s.values)
+	fmt.Fprintf(&b, "%-10s %d\n", "Empty", s.empty)
+	fmt.Fprintf(&b, "%-10s %s\n", "Distinct", distinct)
+
+	if len(s.numbers) > 0 {
+		sorted := slices.Clone(s.numbers)
+		slices.Sort(sorted)
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%-10s %d\n", "Numeric", len(sorted))
+		fmt.Fprintf(&b, "%-10s %s\n", "Min", formatStat(sorted[0]))
+		fmt.Fprintf(&b, "%-10s %s\n", "Max", formatStat(sorted[len(sorted)-1]))
+		fmt.Fprintf(&b, "%-10s %s\n", "Sum", formatStat(s.sum))
+		fmt.Fprintf(&b, "%-10s %s\n", "Mean", formatStat(s.sum/float64(len(sorted))))
+		for _, p := range statsPercentiles {
+			fmt.Fprintf(&b, "%-10s %s\n", "P"+formatStat(p), formatStat(percentile(sorted, p)))
+		}
+	}
+
+	if top := s.top(statsTopN); len(top) > 0 {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%10s  %s\n", "Count", "Value")
+		for _, v := range top {
+			fmt.Fprintf(&b, "%10d  %s\n", v.count, v.value)
+		}
+	}
+	return b.String()
+}
+
+// columnStats returns the statistics of the column of the body.
+func (m *Document) columnStats(ctx context.Context, column int) (*columnStats, error) {
+	stats := newColumnStats()
+	err := m.columnRows(ctx, column, func(_ int, _ [][]byte, value string, found bool) error {
+		stats.add(value, found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// showColumnStats computes the statistics of the column of the cursor in the background,
+// and opens the result as a new document when it finishes.
+func (root *Root) showColumnStats(ctx context.Context) {
+	m := root.Doc
+	if !m.ColumnMode {
+		root.setMessage(ErrNotColumnMode.Error())
+		return
+	}
+	column := m.cursorColumn()
+	match := fmt.Sprintf("col%d", column+1)
+	root.setMessagef("stats:%s (%v)Cancel", match, strings.Join(root.cancelKeys, ","))
+
+	root.runJob(ctx, "stats", func(ctx context.Context) (func(context.Context), error) {
+		stats, err := m.columnStats(ctx, column)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) {
+			root.addColumnStats(ctx, m, column, match, stats)
+		}, nil
+	})
+}
+
+// addColumnStats opens the statistics of the column as a new document.
+func (root *Root) addColumnStats(ctx context.Context, m *Document, column int, match string, stats *columnStats) {
+	title := fmt.Sprintf("Column %d", column+1)
+	if name := m.columnName(column); name != "" {
+		title += ": " + name
+	}
+	title += " (" + m.FileName
+	if m.Caption != "" {
+		title += " " + m.Caption
+	}
+	title += ")"
+	doc, err := newStatsDoc(fmt.Sprintf("stats:%s", match), title+"\n\n"+stats.String())
+	if err != nil {
+		root.setMessageLog(err.Error())
+		return
+	}
+	root.addDocument(ctx, doc)
+	root.setMessagef("stats:%s", match)
+}
+
+// newStatsDoc returns the document of the statistics.
+func newStatsDoc(caption string, str string) (*Document, error) {
+	m, err := NewDocument()
+	if err != nil {
+		return nil, err
+	}
+	m.documentType = DocStats
+	m.Caption = caption
+	m.preventReload = true
+	m.seekable = false
+	if err := m.ControlReader(strings.NewReader(str), nil); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,172 @@
+package oviewer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func Test_percentile(t *testing.T) {
+	sorted := []float64{5, 30, 100}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 5},
+		{p: 50, want: 30},
+		{p: 90, want: 100},
+		{p: 100, want: 100},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %v, want 0", got)
+	}
+}
+
+func Test_columnStats_top(t *testing.T) {
+	s := newColumnStats()
+	for _, v := range []string{"b", "a", "c", "a", "b", "a"} {
+		s.add(v, true)
+	}
+	s.add("", true)
+	s.add("", false)
+	want := []statsValue{{value: "a", count: 3}, {value: "b", count: 2}}
+	if got := s.top(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("columnStats.top() = %v, want %v", got, want)
+	}
+	if s.rows != 8 || s.values != 6 || s.empty != 2 {
+		t.Errorf("columnStats rows = %d values = %d empty = %d, want 8 6 2", s.rows, s.values, s.empty)
+	}
+	if len(s.numbers) != 0 {
+		t.Errorf("columnStats numbers = %v, want none", s.numbers)
+	}
+}
+
+func Test_columnStats_distinctLimit(t *testing.T) {
+	limit := statsDistinctLimit
+	statsDistinctLimit = 2
+	defer func() {
+		statsDistinctLimit = limit
+	}()
+	s := newColumnStats()
+	for _, v := range []string{"a", "b", "c", "a"} {
+		s.add(v, true)
+	}
+	if !s.overflow || len(s.freq) != 2 || s.freq["a"] != 2 {
+		t.Errorf("columnStats overflow = %v freq = %v", s.overflow, s.freq)
+	}
+	if !strings.Contains(s.String(), "Distinct   >2\n") {
+		t.Errorf("columnStats.String() = %q", s.String())
+	}
+}
+
+func TestDocument_columnStats(t *testing.T) {
+	m := docHelper(t, "name,latency\na,30\nb,5\nc,\nd,100\ne,1,000\n")
+	m.setDelimiter(",")
+	m.Header = 1
+	stats, err := m.columnStats(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := stats.String()
+	for _, want := range []string{
+		"Rows       5\n",
+		"Values     4\n",
+		"Empty      1\n",
+		"Min        1\n",
+		"Max        100\n",
+		"Sum        136\n",
+		"Mean       34\n",
+		"P50        5\n",
+		"P90        100\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Document.columnStats() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestDocument_columnStatsLarge(t *testing.T) {
+	// The record with a line break spans the chunks, and the chunk after the first is not loaded.
+	var b strings.Builder
+	b.WriteString("name,value\n")
+	for lN := 1; lN < ChunkSize+100; lN++ {
+		switch lN {
+		case ChunkSize - 1:
+			b.WriteString("\"a\n")
+		case ChunkSize:
+			b.WriteString("b\",1\n")
+		default:
+			fmt.Fprintf(&b, "%d,1\n", lN)
+		}
+	}
+	fileName := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(fileName, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := docFileReadHelper(t, fileName)
+	m.setDelimiter(",")
+	m.Header = 1
+	stats, err := m.columnStats(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := ChunkSize + 100 - 2
+	got := stats.String()
+	for _, want := range []string{
+		fmt.Sprintf("Rows       %d\n", rows),
+		fmt.Sprintf("Values     %d\n", rows),
+		fmt.Sprintf("Sum        %d\n", rows),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Document.columnStats() = %q, want %q", got, want)
+		}
+	}
+	if m.store.isLoadedChunk(1, m.seekable) {
+		t.Errorf("chunk 1 is loaded")
+	}
+}
+
+func TestDocument_columnStatsCancel(t *testing.T) {
+	m := docHelper(t, "a\nb\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.columnStats(ctx, 0); err != ErrCancel {
+		t.Errorf("Document.columnStats() error = %v, want %v", err, ErrCancel)
+	}
+}
+
+func TestRoot_showColumnStats(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	root.Doc.Header = 1
+	root.Doc.ColumnMode = true
+	root.Doc.setDelimiter(",")
+	root.Doc.columnCursor = 2
+	root.showColumnStats(context.Background())
+	jobHelper(t, root)
+	if root.DocumentLen() != 2 {
+		t.Fatalf("showColumnStats() documents = %d, want 2", root.DocumentLen())
+	}
+	stats := root.Doc
+	eofHelper(t, stats)
+	if stats.documentType != DocStats {
+		t.Errorf("showColumnStats() document type = %v, want %v", stats.documentType, DocStats)
+	}
+	if got := stats.LineString(0); !strings.HasPrefix(got, "Column 3: latency") {
+		t.Errorf("showColumnStats() title = %q", got)
+	}
+}
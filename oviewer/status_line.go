@@ -67,6 +67,15 @@ func (root *Root) normalLeftStatus() (contents, int) {
 
 // statusDisplay returns the status mode of the document.
 func (root *Root) statusDisplay() string {
+	if root.macro.recording {
+		return "(Recording " + root.macro.name + ")"
+	}
+	if root.visual.active {
+		if root.visual.rectangle {
+			return "(Visual Block)"
+		}
+		return "(Visual)"
+	}
 	if root.Doc.WatchMode {
 		// Watch mode doubles as FollowSection mode.
 		return "(Watch)"
@@ -107,6 +116,22 @@ func (root *Root) inputPrompt() string {
 	return prompt.String()
 }
 
+// columnStatus returns the number and the name of the column of the cursor in the column mode.
+func (root *Root) columnStatus() string {
+	m := root.Doc
+	if !m.ColumnMode {
+		return ""
+	}
+	n := m.cursorColumn()
+	if n < 0 {
+		return ""
+	}
+	if name := m.columnName(n); name != "" {
+		return fmt.Sprintf("col%d:%s", n+1, name)
+	}
+	return fmt.Sprintf("col%d", n+1)
+}
+
 // rightStatus returns the status of the right side.
 func (root *Root) rightStatus() contents {
 	next := ""
@@ -117,5 +142,11 @@ func (root *Root) rightStatus() contents {
 	if atomic.LoadInt32(&root.Doc.tmpFollow) == 1 {
 		str = fmt.Sprintf("(?/%d%s)", root.Doc.storeEndNum(), next)
 	}
+	if column := root.columnStatus(); column != "" {
+		str = column + " " + str
+	}
+	if pending := root.pendingKeyString(); pending != "" {
+		str = pending + " " + str
+	}
 	return StrToContents(str, -1)
 }
@@ -93,6 +93,32 @@ func TestRoot_leftStatus2(t *testing.T) {
 	}
 }
 
+func TestRoot_columnStatus(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "sort.csv"))
+	root.Doc.setDelimiter(",")
+	if got := root.columnStatus(); got != "" {
+		t.Errorf("columnStatus() = %q, want empty", got)
+	}
+	root.Doc.ColumnMode = true
+	root.Doc.columnCursor = 2
+	if got, want := root.columnStatus(), "col3"; got != want {
+		t.Errorf("columnStatus() = %q, want %q", got, want)
+	}
+	root.Doc.Header = 1
+	root.Doc.ColumnOrder = []int{2}
+	if got, want := root.columnStatus(), "col3:latency"; got != want {
+		t.Errorf("columnStatus() = %q, want %q", got, want)
+	}
+	root.Doc.columnCursor = 0
+	if got, want := root.columnStatus(), "col2:size"; got != want {
+		t.Errorf("columnStatus() = %q, want %q", got, want)
+	}
+}
+
 func TestRoot_statusDisplay(t *testing.T) {
 	tcellNewScreen = fakeScreen
 	defer func() {
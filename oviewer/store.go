@@ -118,6 +118,17 @@ func (s *store) evictChunksMem(chunkNum int) {
 	atomic.StoreInt32(&s.startNum, int32((k+1)*ChunkSize))
 }
 
+// chunkLines returns the lines of the chunk in memory.
+func (s *store) chunkLines(chunkNum int) [][]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if chunkNum >= len(s.chunks) {
+		return nil
+	}
+	lines := s.chunks[chunkNum].lines
+	return lines[:len(lines):len(lines)]
+}
+
 // unloadChunk unloads the chunk from memory.
 func (s *store) unloadChunk(chunkNum int) {
 	s.loadedChunks.Remove(chunkNum)
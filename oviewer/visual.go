@@ -0,0 +1,227 @@
+package oviewer
+
+import (
+	"context"
+)
+
+// visualSelect represents the keyboard-driven selection.
+// The selection is held in document coordinates
+// (line number and x position in the line), not screen coordinates.
+type visualSelect struct {
+	// active is true while visual mode is on.
+	active bool
+	// rectangle is true for block (rectangle) selection.
+	rectangle bool
+	// startLN is the line number where the selection started.
+	startLN int
+	// cursorLN is the line number of the cursor line.
+	cursorLN int
+	// startX is the x position where the block selection started.
+	startX int
+	// cursorX is the x position of the cursor in block selection.
+	cursorX int
+}
+
+// visualCursorStyle is the style of the cursor line in visual mode.
+var visualCursorStyle = OVStyle{Underline: true}
+
+// toggleVisualLine toggles the line-wise visual mode.
+func (root *Root) toggleVisualLine(context.Context) {
+	root.toggleVisual(false)
+}
+
+// toggleVisualBlock toggles the block (rectangle) visual mode.
+func (root *Root) toggleVisualBlock(context.Context) {
+	root.toggleVisual(true)
+}
+
+// toggleVisual starts the visual mode from the top line of the screen,
+// or ends it if the same kind of visual mode is already on.
+func (root *Root) toggleVisual(rectangle bool) {
+	if root.visual.active && root.visual.rectangle == rectangle {
+		root.endVisual()
+		root.setMessage("")
+		return
+	}
+	root.resetSelect()
+
+	m := root.Doc
+	lN := m.topLN + m.firstLine()
+	if root.visual.active {
+		// Switch between line-wise and block while keeping the cursor.
+		lN = root.visual.cursorLN
+	}
+	x := max(0, m.x)
+	root.visual = visualSelect{
+		active:    true,
+		rectangle: rectangle,
+		startLN:   lN,
+		cursorLN:  lN,
+		startX:    x,
+		cursorX:   x,
+	}
+	root.setMessage(root.visualMessage())
+}
+
+// endVisual ends the visual mode.
+func (root *Root) endVisual() {
+	root.visual = visualSelect{}
+}
+
+// visualMessage returns the message displayed in visual mode.
+func (root *Root) visualMessage() string {
+	if root.visual.rectangle {
+		return "-- VISUAL BLOCK --"
+	}
+	return "-- VISUAL LINE --"
+}
+
+// visualMoveY moves the cursor line up and down in visual mode.
+// Scroll the screen if the cursor goes out of the screen.
+func (root *Root) visualMoveY(n int) {
+	m := root.Doc
+	lN := root.visual.cursorLN + n
+	lN = min(lN, m.BufEndNum()-1)
+	lN = max(lN, m.firstLine())
+	root.visual.cursorLN = lN
+
+	top := m.topLN + m.firstLine()
+	if lN < top {
+		m.moveLimitYUp(top - lN)
+		return
+	}
+	if m.bottomLN > top && lN >= m.bottomLN {
+		m.moveYDown(lN - m.bottomLN + 1)
+	}
+}
+
+// visualMoveX moves the cursor left and right in block visual mode.
+func (root *Root) visualMoveX(n int) {
+	m := root.Doc
+	x := max(0, root.visual.cursorX+n)
+	root.visual.cursorX = x
+	if m.WrapMode {
+		return
+	}
+	width := root.scr.vWidth - root.scr.startX
+	if x < m.x {
+		m.x = x
+	}
+	if x >= m.x+width {
+		m.x = x - width + 1
+	}
+}
+
+// visualClamp keeps the cursor line within the displayed lines
+// after moving by page or section.
+func (root *Root) visualClamp() {
+	m := root.Doc
+	top := m.topLN + m.firstLine()
+	bottom := max(top, m.bottomLN-1)
+	root.visual.cursorLN = max(root.visual.cursorLN, top)
+	root.visual.cursorLN = min(root.visual.cursorLN, bottom)
+}
+
+// lineRange returns the selected line range.
+func (v visualSelect) lineRange() (int, int) {
+	if v.startLN > v.cursorLN {
+		return v.cursorLN, v.startLN
+	}
+	return v.startLN, v.cursorLN
+}
+
+// xRange returns the selected x range in block visual mode.
+func (v visualSelect) xRange() (int, int) {
+	if v.startX > v.cursorX {
+		return v.cursorX, v.startX
+	}
+	return v.startX, v.cursorX
+}
+
+// visualString returns the string of the visual selection.
+// The selected lines are expanded into a SCR covering the whole range,
+// so that the same conversion as the mouse selection can be used.
+func (root *Root) visualString() (string, error) {
+	m := root.Doc
+	start, end := root.visual.lineRange()
+	scr := SCR{
+		lines:   make(map[int]LineC, end-start+1),
+		numbers: make([]LineNumber, 0, end-start+1),
+		vWidth:  root.scr.vWidth,
+	}
+	maxX := 0
+	for lN := start; lN <= end; lN++ {
+		line := m.getLineC(lN, m.TabWidth)
+		scr.lines[lN] = line
+		scr.numbers = append(scr.numbers, newLineNumber(lN, 0))
+		maxX = max(maxX, len(line.lc))
+	}
+	y2 := len(scr.numbers) - 1
+
+	if root.visual.rectangle {
+		x1, x2 := root.visual.xRange()
+		return scr.rectangleToString(m, x1-m.x, 0, x2-m.x, y2)
+	}
+	str, err := scr.lineRangeToString(m, -m.x, 0, maxX-m.x, y2)
+	if err != nil {
+		return "", err
+	}
+	return str + "\n", nil
+}
+
+// visualCopy copies the visual selection to the clipboard.
+func (root *Root) visualCopy(context.Context) {
+	if !root.visual.active {
+		root.setMessage("No visual selection")
+		return
+	}
+	str, err := root.visualString()
+	root.endVisual()
+	if err != nil {
+		root.setMessageLogf("visual copy: %s", err)
+		return
+	}
+	root.copyClipboard(str, "Copy")
+}
+
+// visualMark marks all lines in the visual selection.
+func (root *Root) visualMark() {
+	m := root.Doc
+	start, end := root.visual.lineRange()
+	for lN := start; lN <= end; lN++ {
+		m.marked = remove(m.marked, lN)
+		m.marked = append(m.marked, lN)
+	}
+	root.endVisual()
+	root.setMessagef("Marked to line %d-%d", start-m.firstLine()+1, end-m.firstLine()+1)
+}
+
+// drawVisual highlights the visual selection and the cursor line.
+func (root *Root) drawVisual() {
+	m := root.Doc
+	root.visualClamp()
+	start, end := root.visual.lineRange()
+	x1, x2 := root.visual.xRange()
+	for y := m.headerHeight; y < root.scr.vHeight-statusLine; y++ {
+		ln := root.scr.lineNumber(y)
+		if ln.number < start || ln.number > end {
+			continue
+		}
+		if !root.visual.rectangle {
+			root.reverseLine(y, root.scr.startX, root.scr.vWidth, true)
+		} else {
+			// Convert the position in the line to the screen position.
+			left := m.x
+			if m.WrapMode {
+				line := root.scr.lines[ln.number]
+				left = root.scr.branchWidth(line.lc, ln.wrap)
+			}
+			sx1 := max(root.scr.startX+x1-left, root.scr.startX)
+			sx2 := min(root.scr.startX+x2-left+1, root.scr.vWidth)
+			root.reverseLine(y, sx1, sx2, true)
+		}
+		if ln.number == root.visual.cursorLN {
+			root.yStyle(y, visualCursorStyle)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package oviewer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRoot_visualString(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	type fields struct {
+		rectangle bool
+		moveY     int
+		moveX     int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "testLine",
+			fields: fields{rectangle: false, moveY: 0},
+			want:   "| test1 | test2 | test3 |a\n",
+		},
+		{
+			name:   "testLines",
+			fields: fields{rectangle: false, moveY: 2},
+			want:   "| test1 | test2 | test3 |a\n| 1     | 2     | 3     |\n| 4     | 5     | 6     |\n",
+		},
+		{
+			name:   "testBlock",
+			fields: fields{rectangle: true, moveY: 1, moveX: 6},
+			want:   "| test1\n| 1    \n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := rootFileReadHelper(t, filepath.Join(testdata, "column.txt"))
+			root.prepareScreen()
+			ctx := context.Background()
+			root.draw(ctx)
+			root.toggleVisual(tt.fields.rectangle)
+			root.visualMoveY(tt.fields.moveY)
+			root.visualMoveX(tt.fields.moveX)
+			got, err := root.visualString()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Root.visualString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Root.visualString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_visualMark(t *testing.T) {
+	tcellNewScreen = fakeScreen
+	defer func() {
+		tcellNewScreen = tcell.NewScreen
+	}()
+	root := rootFileReadHelper(t, filepath.Join(testdata, "column.txt"))
+	root.prepareScreen()
+	ctx := context.Background()
+	root.draw(ctx)
+	root.toggleVisualLine(ctx)
+	root.moveDownOne(ctx)
+	root.moveDownOne(ctx)
+	root.addMark(ctx)
+	if root.visual.active {
+		t.Errorf("visual mode is still active")
+	}
+	want := []int{0, 1, 2}
+	if len(root.Doc.marked) != len(want) {
+		t.Fatalf("marked = %v, want %v", root.Doc.marked, want)
+	}
+	for i, lN := range want {
+		if root.Doc.marked[i] != lN {
+			t.Errorf("marked = %v, want %v", root.Doc.marked, want)
+		}
+	}
+}